@@ -6,6 +6,8 @@ package youless
 
 import (
 	"context"
+	"sync"
+	"time"
 )
 
 type DeviceInfoResponse struct {
@@ -14,10 +16,87 @@ type DeviceInfoResponse struct {
 	MAC      string `json:"mac"`
 }
 
+// FirmwareVersion parses Firmware into its major, minor and patch numbers and
+// variant suffix, e.g. "1.5.1-EL" results in 1, 5, 1 and "EL". The variant is
+// empty when the firmware has no suffix. Pre-release markers like "beta2" or
+// "rc1" are skipped. It returns an ErrInvalidFirmware error when Firmware is
+// not a valid version.
+func (r DeviceInfoResponse) FirmwareVersion() (major, minor, patch int, variant string, err error) {
+	return parseFirmware(r.Firmware)
+}
+
+// GetDeviceInfo retrieves the DeviceInfoResponse from the device. When the
+// Requester caches the response, e.g. a Client created with
+// WithDeviceInfoCache, the cached response is returned while it is valid.
 func (api *apiRequester) GetDeviceInfo(ctx context.Context) (DeviceInfoResponse, error) {
+	cache, _ := api.Requester.(deviceInfoCacher)
+	if cache != nil {
+		if info, ok := cache.cachedDeviceInfo(); ok {
+			return info, nil
+		}
+	}
+
+	// concurrent calls during a cache miss are grouped by Request
 	var res DeviceInfoResponse
 	if err := api.Request(withFuncName(ctx, "GetDeviceInfo"), "d", &res); err != nil {
 		return res, err
 	}
+	if cache != nil {
+		cache.cacheDeviceInfo(res)
+	}
 	return res, nil
 }
+
+// deviceInfoCacher is implemented by Requesters which cache the
+// DeviceInfoResponse, e.g. Client.
+type deviceInfoCacher interface {
+	cachedDeviceInfo() (DeviceInfoResponse, bool)
+	cacheDeviceInfo(info DeviceInfoResponse)
+}
+
+// deviceInfoCache caches the DeviceInfoResponse of a Client for ttl, see
+// WithDeviceInfoCache.
+type deviceInfoCache struct {
+	mut     sync.Mutex
+	ttl     time.Duration
+	info    *DeviceInfoResponse
+	expires time.Time
+}
+
+func (dc *deviceInfoCache) load() (DeviceInfoResponse, bool) {
+	dc.mut.Lock()
+	defer dc.mut.Unlock()
+
+	if dc.info == nil || time.Now().After(dc.expires) {
+		return DeviceInfoResponse{}, false
+	}
+	return *dc.info, true
+}
+
+func (dc *deviceInfoCache) store(info DeviceInfoResponse) {
+	dc.mut.Lock()
+	dc.info = &info
+	dc.expires = time.Now().Add(dc.ttl)
+	dc.mut.Unlock()
+}
+
+func (dc *deviceInfoCache) reset() {
+	dc.mut.Lock()
+	dc.info = nil
+	dc.mut.Unlock()
+}
+
+// cachedDeviceInfo returns the DeviceInfoResponse cached by
+// WithDeviceInfoCache, until its ttl expires or the Client is closed.
+func (c *Client) cachedDeviceInfo() (DeviceInfoResponse, bool) {
+	if c.infoCache.ttl <= 0 {
+		return DeviceInfoResponse{}, false
+	}
+	return c.infoCache.load()
+}
+
+func (c *Client) cacheDeviceInfo(info DeviceInfoResponse) {
+	if c.infoCache.ttl > 0 {
+		c.infoCache.store(info)
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDeviceInfoCache(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"model":"LS120","fw":"1.6.1-EL","mac":"72:b8:ad:14:16:2e"}`))
+	}))
+	defer srv.Close()
+
+	want := DeviceInfoResponse{Model: "LS120", Firmware: "1.6.1-EL", MAC: "72:b8:ad:14:16:2e"}
+	ctx := context.Background()
+
+	t.Run("cached", func(t *testing.T) {
+		calls.Store(0)
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithDeviceInfoCache(time.Minute))
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				have, err := c.GetDeviceInfo(ctx)
+				assert.NoError(t, err)
+				assert.Equal(t, want, have)
+			}()
+		}
+		wg.Wait()
+
+		have, err := c.GetDeviceInfo(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, want, have)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("expired", func(t *testing.T) {
+		calls.Store(0)
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithDeviceInfoCache(time.Millisecond))
+		assert.NoError(t, err)
+
+		_, _ = c.GetDeviceInfo(ctx)
+		time.Sleep(5 * time.Millisecond)
+		_, _ = c.GetDeviceInfo(ctx)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+	t.Run("close", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithDeviceInfoCache(time.Minute))
+		assert.NoError(t, err)
+
+		_, _ = c.GetDeviceInfo(ctx)
+		assert.NoError(t, c.Close())
+
+		_, ok := c.infoCache.load()
+		assert.False(t, ok)
+	})
+	t.Run("snapshot", func(t *testing.T) {
+		var hits atomic.Int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/d":
+				hits.Add(1)
+				_, _ = w.Write([]byte(`{"model":"LS120","fw":"1.6.1-EL"}`))
+			case "/e":
+				_, _ = w.Write([]byte(`[{"pwr":350}]`))
+			case "/f":
+				_, _ = w.Write([]byte(`{"v1":231.2}`))
+			}
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithDeviceInfoCache(time.Minute))
+		assert.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err = c.GetSnapshot(ctx)
+			assert.NoError(t, err)
+		}
+		_, err = c.Capabilities(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(1), hits.Load())
+	})
+	t.Run("disabled", func(t *testing.T) {
+		calls.Store(0)
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, _ = c.GetDeviceInfo(ctx)
+		_, _ = c.GetDeviceInfo(ctx)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestDeviceInfoResponse_FirmwareVersion(t *testing.T) {
+	major, minor, patch, variant, err := DeviceInfoResponse{Firmware: "1.5.1-EL"}.FirmwareVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 5, 1}, []int{major, minor, patch})
+	assert.Equal(t, "EL", variant)
+
+	_, _, _, _, err = DeviceInfoResponse{}.FirmwareVersion()
+	assert.ErrorIs(t, err, ErrInvalidFirmware)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrInvalidMonth       errors.Msg = "invalid month"
+	ErrFutureMonth        errors.Msg = "month is in the future"
+	ErrHistoryUnavailable errors.Msg = "history of month is no longer available"
+)
+
+// HistoryResponse contains the per-day totals of a single month.
+type HistoryResponse struct {
+	Unit  Unit
+	Year  int
+	Month time.Month
+	// Values contains a TimedValue for each day of the month, aligned to
+	// midnight.
+	Values []TimedValue
+}
+
+// GetHistory retrieves the daily totals of Utility u for the given month. The
+// device only keeps the most recent occurrence of each month, it returns an
+// ErrHistoryUnavailable error when the month is overwritten by a more recent
+// year. An ErrFutureMonth error is returned when the month is in the future
+// relative to the device's clock.
+func (api *apiRequester) GetHistory(ctx context.Context, u Utility, year int, month time.Month) (HistoryResponse, error) {
+	if month < time.January || month > time.December {
+		return HistoryResponse{}, errors.Wrapf(ErrInvalidMonth, "month %d", month)
+	}
+
+	// the month view's page index is the month number, each page contains the
+	// values of the most recent occurrence of that month
+	res, err := api.GetLog(ctx, u, PerDay, uint(month))
+	if err != nil {
+		return HistoryResponse{}, err
+	}
+
+	tm := res.Time()
+	if year > tm.Year() || (year == tm.Year() && month > tm.Month()) {
+		return HistoryResponse{}, errors.Wrapf(ErrFutureMonth, "%s %d", month, year)
+	}
+	if year != tm.Year() || month != tm.Month() {
+		return HistoryResponse{}, errors.Wrapf(ErrHistoryUnavailable, "%s %d", month, year)
+	}
+
+	values, err := res.TimedValues()
+	if err != nil {
+		return HistoryResponse{}, err
+	}
+
+	// day 0 of the next month is the last day of the requested month, which
+	// takes leap years into account
+	days := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if len(values) > days {
+		values = values[:days]
+	}
+	for i := range values {
+		values[i].Time = time.Date(year, month, i+1, 0, 0, 0, 0, tm.Location())
+	}
+
+	return HistoryResponse{
+		Unit:   res.Unit,
+		Year:   year,
+		Month:  month,
+		Values: values,
+	}, nil
+}
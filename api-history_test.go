@@ -0,0 +1,50 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_GetHistory(t *testing.T) {
+	// february 2024 is a leap year, the device returns a fixed amount of values
+	pages := map[string]string{
+		"V?m=2&f=j": `{"un":"kWh","tm":"2024-02-01T00:00:00","dt":86400,"val":["1","2","3","4","5","6","7","8","9","10","11","12","13","14","15","16","17","18","19","20","21","22","23","24","25","26","27","28","29","0","0",""]}`,
+		"V?m=3&f=j": `{"un":"kWh","tm":"2023-03-01T00:00:00","dt":86400,"val":["1",""]}`,
+	}
+	api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+		data, ok := pages[path]
+		if !ok {
+			t.Fatalf("unexpected request to %s", path)
+		}
+		return json.Unmarshal([]byte(data), out)
+	})}
+
+	t.Run("leap year", func(t *testing.T) {
+		have, err := api.GetHistory(context.Background(), Electricity, 2024, time.February)
+		assert.NoError(t, err)
+		assert.Equal(t, KWh, have.Unit)
+		assert.Len(t, have.Values, 29)
+		assert.Equal(t, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.Local), have.Values[28].Time)
+		assert.Equal(t, int64(29), have.Values[28].Value)
+	})
+	t.Run("future", func(t *testing.T) {
+		_, err := api.GetHistory(context.Background(), Electricity, 2024, time.March)
+		assert.ErrorIs(t, err, ErrFutureMonth)
+	})
+	t.Run("unavailable", func(t *testing.T) {
+		_, err := api.GetHistory(context.Background(), Electricity, 2022, time.March)
+		assert.ErrorIs(t, err, ErrHistoryUnavailable)
+	})
+	t.Run("invalid month", func(t *testing.T) {
+		_, err := api.GetHistory(context.Background(), Electricity, 2024, 13)
+		assert.ErrorIs(t, err, ErrInvalidMonth)
+	})
+}
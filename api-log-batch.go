@@ -0,0 +1,85 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-pogo/errors"
+)
+
+// DefaultBatchConcurrency is the default maximum number of requests
+// GetLogsBatch executes concurrently.
+const DefaultBatchConcurrency = 4
+
+const ErrInvalidBatchConcurrency errors.Msg = "batch concurrency must be positive"
+
+// LogRequest describes a single GetLog call of GetLogsBatch.
+type LogRequest struct {
+	Utility  Utility
+	Interval Interval
+	Page     uint
+}
+
+// LogResult is the result of a LogRequest executed by GetLogsBatch. Err is nil
+// when the request succeeded.
+type LogResult struct {
+	LogRequest
+	Response LogResponse
+	Err      error
+}
+
+// GetLogsBatch concurrently executes GetLog for each LogRequest in reqs, with
+// at most DefaultBatchConcurrency requests at the same time (see
+// WithBatchConcurrency). The returned LogResults are in the same order as
+// reqs. When any of the requests fail, the results of the successful requests
+// are still returned together with the errors of the failed ones. Each
+// request is made using GetLog, so any rate limit and the grouping of
+// identical requests of the Client apply as usual.
+func (api *apiRequester) GetLogsBatch(ctx context.Context, reqs []LogRequest) ([]LogResult, error) {
+	limit := DefaultBatchConcurrency
+	if l, ok := api.Requester.(batchLimiter); ok && l.BatchConcurrency() > 0 {
+		limit = l.BatchConcurrency()
+	}
+	if limit > len(reqs) {
+		limit = len(reqs)
+	}
+
+	res := make([]LogResult, len(reqs))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(limit)
+	for n := 0; n < limit; n++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				req := reqs[i]
+				res[i].LogRequest = req
+				res[i].Response, res[i].Err = api.GetLog(ctx, req.Utility, req.Interval, req.Page)
+			}
+		}()
+	}
+	for i := range reqs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var err error
+	for _, r := range res {
+		if r.Err != nil {
+			errors.AppendInto(&err, errors.Wrapf(r.Err, "%s log %s page %d", r.Utility, r.Interval, r.Page))
+		}
+	}
+	return res, err
+}
+
+// batchLimiter is implemented by Requesters which can be configured with a
+// maximum number of concurrent requests of GetLogsBatch.
+type batchLimiter interface {
+	BatchConcurrency() int
+}
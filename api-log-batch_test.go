@@ -0,0 +1,101 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type batchRequester struct {
+	requesterFunc
+	limit int
+}
+
+func (r batchRequester) BatchConcurrency() int { return r.limit }
+
+func TestAPIRequester_GetLogsBatch(t *testing.T) {
+	reqs := []LogRequest{
+		{Utility: Electricity, Interval: PerMin, Page: 1},
+		{Utility: Gas, Interval: PerHour, Page: 2},
+		{Utility: Water, Interval: PerHour, Page: 1},
+		{Utility: Electricity, Interval: PerHour, Page: 3},
+		{Utility: Electricity, Interval: PerDay, Page: 1},
+	}
+
+	t.Run("in order", func(t *testing.T) {
+		var active, peak atomic.Int32
+		api := &apiRequester{batchRequester{
+			limit: 2,
+			requesterFunc: func(_ context.Context, path string, out any) error {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				out.(*LogResponse).Timestamp = path
+				return nil
+			},
+		}}
+
+		have, err := api.GetLogsBatch(context.Background(), reqs)
+		assert.NoError(t, err)
+		assert.Len(t, have, len(reqs))
+		assert.Equal(t, int32(2), peak.Load())
+		assert.Equal(t, []string{"V?h=1&f=j", "W?d=2&f=j", "K?d=1&f=j", "V?d=3&f=j", "V?m=1&f=j"}, timestamps(have))
+		for i, res := range have {
+			assert.Equal(t, reqs[i], res.LogRequest)
+			assert.NoError(t, res.Err)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		wantErr := errors.New("some error")
+		api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+			if strings.HasPrefix(path, "W") {
+				return wantErr
+			}
+			return nil
+		})}
+
+		have, err := api.GetLogsBatch(context.Background(), reqs)
+		assert.ErrorIs(t, err, wantErr)
+		assert.Len(t, have, len(reqs))
+		for i, res := range have {
+			if i == 1 {
+				assert.ErrorIs(t, res.Err, wantErr)
+			} else {
+				assert.NoError(t, res.Err)
+			}
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(context.Context, string, any) error {
+			t.Fatal("unexpected request")
+			return nil
+		})}
+
+		have, err := api.GetLogsBatch(context.Background(), nil)
+		assert.NoError(t, err)
+		assert.Empty(t, have)
+	})
+}
+
+func timestamps(res []LogResult) []string {
+	out := make([]string, len(res))
+	for i, r := range res {
+		out[i] = r.Response.Timestamp
+	}
+	return out
+}
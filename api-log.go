@@ -6,6 +6,7 @@ package youless
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -14,32 +15,37 @@ import (
 	"github.com/go-pogo/errors"
 )
 
-//goland:noinspection GoUnusedConst
 const (
-	Watt       Unit = "Watt"
-	KWh        Unit = "kWh"
-	Liter      Unit = "L"
-	CubicMeter Unit = "m3"
-
-	ErrInvalidLogPage = "page cannot be <= 0; index starts at 1"
+	ErrInvalidLogPage             = "page cannot be <= 0; index starts at 1"
+	ErrInvalidLogMonth errors.Msg = "page of day log cannot be > 12; page is the month number"
 )
 
-type Unit string
-
-func (u Unit) String() string { return string(u) }
-
 type LogResponse struct {
 	Unit      Unit     `json:"un"`
 	Timestamp string   `json:"tm"`
-	Interval  Interval `json:"dt"`
+	Interval  Interval `json:"dt" unit:"s"`
 	RawValues []string `json:"val"`
+
+	loc *time.Location
 }
 
 // GetLog retrieves the log data for the given Utility and Interval at the
-// provided page.
+// provided page. For PerMin, Per10min and PerHour, page 1 contains the newest
+// values and each following page contains older values. For PerDay, page is
+// the month number (1 is January) and contains the daily values of the most
+// recent occurrence of that month. Use GetLatestLog to get the page with the
+// most recent values.
 // Note: the page index starts at 1 and not 0.
 func (api *apiRequester) GetLog(ctx context.Context, u Utility, i Interval, page uint) (LogResponse, error) {
-	if i == PerMin && (u == Gas || u == Water) {
+	endpoint, err := u.EndpointE()
+	if err != nil {
+		return LogResponse{}, err
+	}
+	param, err := i.ParamE()
+	if err != nil {
+		return LogResponse{}, err
+	}
+	if !supports(u, i) {
 		return LogResponse{}, errors.WithStack(&UnsupportedIntervalError{
 			Utility:  u,
 			Interval: i,
@@ -48,23 +54,56 @@ func (api *apiRequester) GetLog(ctx context.Context, u Utility, i Interval, page
 	if page <= 0 {
 		return LogResponse{}, errors.New(ErrInvalidLogPage)
 	}
+	if !withinHistory(i, page) {
+		return LogResponse{}, errors.New(ErrInvalidLogMonth)
+	}
 
 	var res LogResponse
-	err := api.Request(
+	err = api.Request(
 		withFuncName(ctx, "GetLog"),
-		fmt.Sprintf("%s?%c=%d&f=j", u.Endpoint(), i.Param(), page),
+		fmt.Sprintf("%s?%c=%d&f=j", endpoint, param, page),
 		&res,
 	)
+	res.loc = api.location()
 	return res, err
 }
 
+// GetLogPage is similar to GetLog, except it also reports whether an older
+// page may contain values. This is false when page is the last page the
+// device is assumed to keep for Interval i, see Interval.MaxPages, or when
+// page does not contain any values. Pagination loops can use hasMore to know
+// when to stop. For PerDay the page is a month number instead of an age, so
+// hasMore is always false.
+func (api *apiRequester) GetLogPage(ctx context.Context, u Utility, i Interval, page uint) (res LogResponse, hasMore bool, err error) {
+	res, err = api.GetLog(ctx, u, i, page)
+	if err != nil {
+		return res, false, err
+	}
+	if i == PerDay || page >= i.MaxPages() {
+		return res, false, nil
+	}
+
+	_, hasMore = res.lastTime()
+	return res, hasMore, nil
+}
+
 const LogTimeLayout = "2006-01-02T15:04:05"
 
+// Time returns Timestamp as time.Time. The timestamp is the device's local
+// time, it is interpreted in the location of the Client which requested the
+// log (see WithLocation), or time.Local when unknown.
 func (r LogResponse) Time() time.Time {
-	t, _ := time.Parse(LogTimeLayout, r.Timestamp)
+	t, _ := time.ParseInLocation(LogTimeLayout, r.Timestamp, locationOrLocal(r.loc))
 	return t
 }
 
+// In returns a copy of the LogResponse of which Time, and the times of its
+// values, are interpreted in loc.
+func (r LogResponse) In(loc *time.Location) LogResponse {
+	r.loc = loc
+	return r
+}
+
 func (r LogResponse) TimeOfValue(i uint) time.Time {
 	if i == 0 {
 		return r.Time()
@@ -74,6 +113,28 @@ func (r LogResponse) TimeOfValue(i uint) time.Time {
 	return r.Time().Add(time.Second * time.Duration(i))
 }
 
+// TotalEnergy integrates the power samples, in Watt, over their Interval to
+// the total energy in kWh. Inactive samples are skipped. It returns an
+// ErrIncompatibleUnits error when Unit is not Watt.
+func (r LogResponse) TotalEnergy() (float64, error) {
+	if r.Unit != Watt {
+		return 0, errors.Wrapf(ErrIncompatibleUnits, "cannot integrate %s to %s", r.Unit, KWh)
+	}
+
+	values, err := r.TimedValues()
+	if err != nil {
+		return 0, err
+	}
+
+	var sum int64
+	for _, tv := range values {
+		if !tv.Inactive {
+			sum += tv.Value
+		}
+	}
+	return float64(sum) * float64(r.Interval) / 3600 / 1000, nil
+}
+
 type TimedValue struct {
 	Time     time.Time
 	Value    int64
@@ -93,7 +154,7 @@ func (r LogResponse) TimedValues() ([]TimedValue, error) {
 	end -= 1
 
 	dt := r.Interval.Duration()
-	tm, _ := time.Parse(LogTimeLayout, r.Timestamp)
+	tm := r.Time()
 
 	for i, v := range r.RawValues {
 		if i == end && v == "" {
@@ -119,3 +180,135 @@ func (r LogResponse) TimedValues() ([]TimedValue, error) {
 	}
 	return res, nil
 }
+
+type timedValueJSON struct {
+	Time     time.Time `json:"t"`
+	Value    *int64    `json:"v,omitempty"`
+	Inactive bool      `json:"inactive,omitempty"`
+}
+
+// MarshalJSON marshals the TimedValue to json. An inactive value is marshaled
+// as {"t":"2024-01-28T12:00:00Z","inactive":true}, otherwise as
+// {"t":"2024-01-28T12:00:00Z","v":123}.
+func (tv TimedValue) MarshalJSON() ([]byte, error) {
+	v := timedValueJSON{Time: tv.Time, Inactive: tv.Inactive}
+	if !tv.Inactive {
+		v.Value = &tv.Value
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON unmarshals json data created by MarshalJSON into TimedValue.
+func (tv *TimedValue) UnmarshalJSON(data []byte) error {
+	var v timedValueJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*tv = TimedValue{Time: v.Time, Inactive: v.Inactive}
+	if v.Value != nil {
+		tv.Value = *v.Value
+	}
+	return nil
+}
+
+// MarshalJSON marshals the LogResponse to json, with its raw values parsed
+// to TimedValues.
+func (r LogResponse) MarshalJSON() ([]byte, error) {
+	values, err := r.TimedValues()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Unit     Unit         `json:"unit"`
+		Time     time.Time    `json:"time"`
+		Interval Interval     `json:"interval"`
+		Values   []TimedValue `json:"values"`
+	}{
+		Unit:     r.Unit,
+		Time:     r.Time(),
+		Interval: r.Interval,
+		Values:   values,
+	})
+}
+
+// UnmarshalJSON unmarshals both the json data of the device's log pages and
+// the json data created by MarshalJSON into LogResponse. Inactive values
+// created by MarshalJSON are restored as "*".
+func (r *LogResponse) UnmarshalJSON(data []byte) error {
+	type alias LogResponse
+	var v struct {
+		alias
+		Unit     *Unit        `json:"unit"`
+		Time     *time.Time   `json:"time"`
+		Interval *Interval    `json:"interval"`
+		Values   []TimedValue `json:"values"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*r = LogResponse(v.alias)
+	if v.Time == nil {
+		return nil
+	}
+
+	r.Timestamp = v.Time.Format(LogTimeLayout)
+	r.loc = v.Time.Location()
+	if v.Unit != nil {
+		r.Unit = *v.Unit
+	}
+	if v.Interval != nil {
+		r.Interval = *v.Interval
+	}
+	r.RawValues = make([]string, len(v.Values))
+	for i, tv := range v.Values {
+		if tv.Inactive {
+			r.RawValues[i] = "*"
+		} else {
+			r.RawValues[i] = strconv.FormatInt(tv.Value, 10)
+		}
+	}
+	return nil
+}
+
+// GetLatestLog retrieves the log page with the most recent values for the
+// given Utility and Interval. Trailing empty values, for moments which are
+// not yet reached, are removed from the result.
+// For PerMin, Per10min and PerHour this is page 1, as page 1 contains the
+// newest values and higher pages contain older values. For PerDay the page is
+// the month number, the month of the current time in the device's location
+// is used. When the device's clock lags behind and did not yet reach this
+// month, the previous month is used.
+func (api *apiRequester) GetLatestLog(ctx context.Context, u Utility, i Interval) (LogResponse, error) {
+	if i != PerDay {
+		res, err := api.GetLog(ctx, u, i, 1)
+		return res.trimEmpty(), err
+	}
+
+	now := time.Now().In(locationOrLocal(api.location()))
+	res, err := api.GetLog(ctx, u, i, uint(now.Month()))
+	if err != nil {
+		return res, err
+	}
+
+	// the page contains last year's values when the device did not yet
+	// reach the current month
+	if tm := res.Time(); tm.Year() < now.Year() {
+		prev := now.AddDate(0, 0, -now.Day())
+		if res, err = api.GetLog(ctx, u, i, uint(prev.Month())); err != nil {
+			return res, err
+		}
+	}
+	return res.trimEmpty(), nil
+}
+
+// trimEmpty removes all trailing empty values from RawValues.
+func (r LogResponse) trimEmpty() LogResponse {
+	end := len(r.RawValues)
+	for end > 0 && strings.TrimSpace(r.RawValues[end-1]) == "" {
+		end--
+	}
+	r.RawValues = r.RawValues[:end]
+	return r
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimedValue_MarshalJSON(t *testing.T) {
+	tm := time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	tests := map[string]TimedValue{
+		`{"t":"2024-01-28T12:00:00Z","v":123}`:         {Time: tm, Value: 123},
+		`{"t":"2024-01-28T12:00:00Z","v":0}`:           {Time: tm},
+		`{"t":"2024-01-28T12:00:00Z","inactive":true}`: {Time: tm, Inactive: true},
+	}
+	for want, tv := range tests {
+		t.Run(want, func(t *testing.T) {
+			have, err := json.Marshal(tv)
+			assert.NoError(t, err)
+			assert.Equal(t, want, string(have))
+
+			var rev TimedValue
+			assert.NoError(t, json.Unmarshal(have, &rev))
+			assert.Equal(t, tv, rev)
+		})
+	}
+}
+
+func TestLogResponse_MarshalJSON(t *testing.T) {
+	r := LogResponse{
+		Unit:      Watt,
+		Timestamp: "2024-01-28T12:00:00",
+		Interval:  PerMin,
+		RawValues: []string{"350", "*", ""},
+		loc:       time.UTC,
+	}
+
+	have, err := json.Marshal(r)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"unit":"Watt","time":"2024-01-28T12:00:00Z","interval":60,"values":[`+
+		`{"t":"2024-01-28T12:00:00Z","v":350},`+
+		`{"t":"2024-01-28T12:01:00Z","inactive":true}]}`,
+		string(have),
+	)
+
+	var rev LogResponse
+	assert.NoError(t, json.Unmarshal(have, &rev))
+	assert.Equal(t, r.Unit, rev.Unit)
+	assert.Equal(t, r.Interval, rev.Interval)
+	assert.Equal(t, r.Time(), rev.Time())
+	assert.Equal(t, []string{"350", "*"}, rev.RawValues)
+
+	again, err := json.Marshal(rev)
+	assert.NoError(t, err)
+	assert.Equal(t, string(have), string(again))
+}
+
+func TestLogResponse_UnmarshalJSON(t *testing.T) {
+	var have LogResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"un":"kWh","tm":"2024-01-28T12:00:00","dt":3600,"val":["1,5","*",""]}`), &have))
+	assert.Equal(t, LogResponse{
+		Unit:      KWh,
+		Timestamp: "2024-01-28T12:00:00",
+		Interval:  PerHour,
+		RawValues: []string{"1,5", "*", ""},
+	}, have)
+}
+
+func TestLogResponse_TimedValues(t *testing.T) {
+	tm := time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	r := LogResponse{
+		Unit:      Watt,
+		Timestamp: "2024-01-28T12:00:00",
+		Interval:  Per10min,
+		RawValues: []string{" 350", "*", "-1500", ""},
+		loc:       time.UTC,
+	}
+
+	have, err := r.TimedValues()
+	assert.NoError(t, err)
+	assert.Equal(t, []TimedValue{
+		{Time: tm, Value: 350},
+		{Time: tm.Add(10 * time.Minute), Inactive: true},
+		{Time: tm.Add(20 * time.Minute), Value: -1500},
+	}, have)
+
+	t.Run("invalid", func(t *testing.T) {
+		r.RawValues = []string{"350", "abc"}
+		_, err := r.TimedValues()
+		assert.Error(t, err)
+	})
+}
+
+func TestAPIRequester_GetLog(t *testing.T) {
+	t.Run("invalid utility", func(t *testing.T) {
+		api := NewAPIRequester(requesterFunc(func(context.Context, string, any) error {
+			t.Fatal("should not be called")
+			return nil
+		}))
+		_, err := api.GetLog(context.Background(), "foo", PerHour, 1)
+		assert.ErrorIs(t, err, ErrInvalidUtility)
+	})
+	t.Run("invalid interval", func(t *testing.T) {
+		api := NewAPIRequester(requesterFunc(func(context.Context, string, any) error {
+			t.Fatal("should not be called")
+			return nil
+		}))
+		_, err := api.GetLog(context.Background(), Electricity, 5, 1)
+		assert.ErrorIs(t, err, ErrInvalidInterval)
+	})
+	t.Run("negative values", func(t *testing.T) {
+		api := NewAPIRequester(requesterFunc(func(_ context.Context, path string, out any) error {
+			assert.Equal(t, "V?w=1&f=j", path)
+			return json.Unmarshal([]byte(`{"un":"Watt","tm":"2024-01-28T12:00:00","dt":600,"val":["120","-42","-1200",""]}`), out)
+		}))
+
+		res, err := api.GetLog(context.Background(), Electricity, Per10min, 1)
+		assert.NoError(t, err)
+
+		have, err := res.TimedValues()
+		assert.NoError(t, err)
+		assert.Len(t, have, 3)
+		assert.Equal(t, int64(-42), have[1].Value)
+		assert.Equal(t, int64(-1200), have[2].Value)
+	})
+}
+
+func TestAPIRequester_GetLogPage(t *testing.T) {
+	tests := map[string]struct {
+		interval Interval
+		page     uint
+		values   string
+		want     bool
+	}{
+		"has values":   {interval: PerHour, page: 1, values: `["350","120",""]`, want: true},
+		"empty page":   {interval: PerHour, page: 42, values: `["","",""]`, want: false},
+		"inactive":     {interval: PerHour, page: 42, values: `["*","*"]`, want: false},
+		"last page":    {interval: PerHour, page: 70, values: `["350","120"]`, want: false},
+		"month":        {interval: PerDay, page: 3, values: `["1.5"]`, want: false},
+		"last month":   {interval: PerDay, page: 12, values: `["1.5"]`, want: false},
+		"last minutes": {interval: PerMin, page: 20, values: `["350"]`, want: false},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := NewAPIRequester(requesterFunc(func(_ context.Context, path string, out any) error {
+				assert.Equal(t, fmt.Sprintf("V?%c=%d&f=j", tc.interval.Param(), tc.page), path)
+				return json.Unmarshal([]byte(`{"un":"Watt","tm":"2024-01-28T12:00:00","dt":3600,"val":`+tc.values+`}`), out)
+			}))
+
+			_, hasMore, err := api.GetLogPage(context.Background(), Electricity, tc.interval, tc.page)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, hasMore)
+		})
+	}
+	t.Run("error", func(t *testing.T) {
+		api := NewAPIRequester(requesterFunc(func(context.Context, string, any) error {
+			t.Fatal("should not be called")
+			return nil
+		}))
+		_, hasMore, err := api.GetLogPage(context.Background(), Electricity, PerHour, 0)
+		assert.Error(t, err)
+		assert.False(t, hasMore)
+	})
+}
+
+func TestLogResponse_TotalEnergy(t *testing.T) {
+	t.Run("watt", func(t *testing.T) {
+		r := LogResponse{
+			Unit:      Watt,
+			Timestamp: "2024-01-28T12:00:00",
+			Interval:  Per10min,
+			RawValues: []string{"600", "*", "1200", "-300", ""},
+		}
+
+		have, err := r.TotalEnergy()
+		assert.NoError(t, err)
+		// (600 + 1200 - 300) W * 10 min = 0.25 kWh
+		assert.InDelta(t, 0.25, have, 1e-9)
+	})
+	t.Run("incompatible unit", func(t *testing.T) {
+		r := LogResponse{Unit: Liter, Interval: Per10min}
+		_, err := r.TotalEnergy()
+		assert.ErrorIs(t, err, ErrIncompatibleUnits)
+	})
+}
+
+func TestAPIRequester_GetLatestLog(t *testing.T) {
+	t.Run("page 1", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+			assert.Equal(t, "V?w=1&f=j", path)
+			return json.Unmarshal([]byte(`{"un":"Watt","tm":"2024-01-28T12:00:00","dt":600,"val":["350","*","","",""]}`), out)
+		})}
+
+		have, err := api.GetLatestLog(context.Background(), Electricity, Per10min)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"350", "*"}, have.RawValues)
+	})
+	t.Run("device lags behind month", func(t *testing.T) {
+		now := time.Now()
+		prev := now.AddDate(0, 0, -now.Day())
+		pages := map[string]string{
+			fmt.Sprintf("V?m=%d&f=j", now.Month()):  `{"un":"kWh","tm":"` + now.AddDate(-1, 0, 1-now.Day()).Format("2006-01-02") + `T00:00:00","dt":86400,"val":["1"]}`,
+			fmt.Sprintf("V?m=%d&f=j", prev.Month()): `{"un":"kWh","tm":"` + prev.AddDate(0, 0, 1-prev.Day()).Format("2006-01-02") + `T00:00:00","dt":86400,"val":["2",""]}`,
+		}
+		api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+			return json.Unmarshal([]byte(pages[path]), out)
+		})}
+
+		have, err := api.GetLatestLog(context.Background(), Electricity, PerDay)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2"}, have.RawValues)
+	})
+}
+
+func TestAPIRequester_GetLog_dayPage(t *testing.T) {
+	api := &apiRequester{requesterFunc(func(context.Context, string, any) error {
+		return nil
+	})}
+
+	_, err := api.GetLog(context.Background(), Electricity, PerDay, 12)
+	assert.NoError(t, err)
+	_, err = api.GetLog(context.Background(), Electricity, PerDay, 13)
+	assert.ErrorIs(t, err, ErrInvalidLogMonth)
+}
@@ -6,9 +6,15 @@ package youless
 
 import (
 	"context"
+	"encoding/json"
+	"math"
 	"time"
+
+	"github.com/go-pogo/errors"
 )
 
+const ErrEmptyResponse errors.Msg = "empty response"
+
 // MeterReadingResponse is the response from the /e endpoint. It is a
 // translation of a P1 telegram, with additional values, to JSON.
 type MeterReadingResponse struct {
@@ -16,6 +22,100 @@ type MeterReadingResponse struct {
 	S0Reading
 	GasReading
 	WaterReading
+
+	// secondaryS0 contains the reading of the second S0 channel of dual
+	// channel devices, it is nil when the device does not report it.
+	secondaryS0 *S0Reading
+}
+
+// secondaryS0JSON contains the json keys of the second S0 channel.
+type secondaryS0JSON struct {
+	S0Timestamp *int64   `json:"ts1"`
+	S0Total     *float64 `json:"cs1" unit:"kWh"`
+	S0          *int64   `json:"ps1" unit:"W"`
+}
+
+// meterReadingNumberKeys contains the json keys of the number fields of a
+// MeterReadingResponse, the keys of integer fields have value true.
+var meterReadingNumberKeys = map[string]bool{
+	"tm": true, "pwr": true,
+	"p1": false, "p2": false, "n1": false, "n2": false, "net": false,
+	"ts0": true, "ps0": true, "cs0": false,
+	"ts1": true, "ps1": true, "cs1": false,
+	"gts": true, "gas": false,
+	"wts": true, "wtr": false,
+}
+
+// UnmarshalJSON unmarshals data into MeterReadingResponse. The reading of the
+// second S0 channel is only set when any of its keys are present in data.
+// Numbers may be either json numbers or strings with European formatted
+// numbers (e.g. "1.234,5"), as emitted by some firmware versions depending on
+// their locale settings.
+func (r *MeterReadingResponse) UnmarshalJSON(data []byte) error {
+	data, err := normalizeDecimals(data, meterReadingNumberKeys)
+	if err != nil {
+		return err
+	}
+
+	type alias MeterReadingResponse
+	var v alias
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+
+	var s0 secondaryS0JSON
+	if err := json.Unmarshal(data, &s0); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*r = MeterReadingResponse(v)
+	if s0.S0Timestamp != nil || s0.S0Total != nil || s0.S0 != nil {
+		r.secondaryS0 = new(S0Reading)
+		if s0.S0Timestamp != nil {
+			r.secondaryS0.S0Timestamp = *s0.S0Timestamp
+		}
+		if s0.S0Total != nil {
+			r.secondaryS0.S0Total = *s0.S0Total
+		}
+		if s0.S0 != nil {
+			r.secondaryS0.S0 = *s0.S0
+		}
+	}
+	return nil
+}
+
+// MarshalJSON marshals MeterReadingResponse to json using the same keys as
+// the device. The reading of the second S0 channel is only included when
+// present, so the result can be unmarshalled again with UnmarshalJSON.
+func (r MeterReadingResponse) MarshalJSON() ([]byte, error) {
+	type alias MeterReadingResponse
+	v := struct {
+		alias
+		S0Timestamp1 *int64   `json:"ts1,omitempty"`
+		S0Total1     *float64 `json:"cs1,omitempty"`
+		S01          *int64   `json:"ps1,omitempty"`
+	}{alias: alias(r)}
+
+	if r.secondaryS0 != nil {
+		v.S0Timestamp1 = &r.secondaryS0.S0Timestamp
+		v.S0Total1 = &r.secondaryS0.S0Total
+		v.S01 = &r.secondaryS0.S0
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return b, nil
+}
+
+// SecondaryS0 returns the reading of the second S0 channel of dual channel
+// devices. It reports false when the device does not report a second channel.
+func (r MeterReadingResponse) SecondaryS0() (S0Reading, bool) {
+	if r.secondaryS0 == nil {
+		return S0Reading{}, false
+	}
+	return *r.secondaryS0, true
 }
 
 type ElectricityReading struct {
@@ -23,23 +123,23 @@ type ElectricityReading struct {
 	Timestamp int64 `json:"tm"`
 	// ElectricityImport1 is the meter reading of total imported low tariff
 	// electricity in kWh (Import 1).
-	ElectricityImport1 float64 `json:"p1"`
+	ElectricityImport1 float64 `json:"p1" unit:"kWh"`
 	// ElectricityImport2 is the meter reading of total imported high tariff
 	// electricity in kWh (Import 2).
-	ElectricityImport2 float64 `json:"p2"`
+	ElectricityImport2 float64 `json:"p2" unit:"kWh"`
 	// ElectricityExport1 is the meter reading of total exported low tariff
 	// electricity in kWh (Export 1).
-	ElectricityExport1 float64 `json:"n1"`
+	ElectricityExport1 float64 `json:"n1" unit:"kWh"`
 	// ElectricityExport2 is the meter reading of total exported high tariff
 	// electricity in kWh (Export 2).
-	ElectricityExport2 float64 `json:"n2"`
+	ElectricityExport2 float64 `json:"n2" unit:"kWh"`
 	// NetElectricity is the total measured electricity which equals
 	// (ElectricityImport1 + ElectricityImport2 - ElectricityExport1 - ElectricityExport2)
 	// (Meterstand).
-	NetElectricity float64 `json:"net"`
+	NetElectricity float64 `json:"net" unit:"kWh"`
 	// Power is the current imported (or negative for exported) electricity
 	// power in Watt (Actueel vermogen).
-	Power int64 `json:"pwr"`
+	Power int64 `json:"pwr" unit:"W"`
 }
 
 type S0Reading struct {
@@ -47,10 +147,10 @@ type S0Reading struct {
 	S0Timestamp int64 `json:"ts0"`
 	// S0Total is the total power in kWh measured by the S0 meter
 	// (S0 meterstand).
-	S0Total float64 `json:"cs0"`
+	S0Total float64 `json:"cs0" unit:"kWh"`
 	// S0 is the current electricity power measured in Watt from the S0 meter
 	// (S0 vermogen).
-	S0 int64 `json:"ps0"`
+	S0 int64 `json:"ps0" unit:"W"`
 }
 
 type GasReading struct {
@@ -58,7 +158,11 @@ type GasReading struct {
 	// reading.
 	GasTimestamp uint64 `json:"gts"`
 	// GasTotal is the meter reading of delivered gas (in m3) to client.
-	GasTotal float64 `json:"gas"`
+	GasTotal float64 `json:"gas" unit:"m3"`
+
+	// loc is the location of the device, as set on the Client which
+	// requested the reading, see WithLocation
+	loc *time.Location
 }
 
 type WaterReading struct {
@@ -66,15 +170,128 @@ type WaterReading struct {
 	// meter reading.
 	WaterTimestamp uint64 `json:"wts"`
 	// WaterTotal is the meter reading of delivered water (in m3) to client.
-	WaterTotal float64 `json:"wtr"`
+	WaterTotal float64 `json:"wtr" unit:"m3"`
+
+	// loc is the location of the device, as set on the Client which
+	// requested the reading, see WithLocation
+	loc *time.Location
 }
 
 func (api *apiRequester) GetMeterReading(ctx context.Context) (MeterReadingResponse, error) {
 	var res []MeterReadingResponse
 	if err := api.Request(withFuncName(ctx, "GetMeterReading"), "e", &res); err != nil {
+		if !errors.Is(err, ErrUnsupportedByFirmware) {
+			return MeterReadingResponse{}, err
+		}
+		if f, ok := api.Requester.(telegramFallbacker); ok && f.TelegramFallback() {
+			reading, telegramErr := api.meterReadingFromTelegram(ctx)
+			if !errors.Is(telegramErr, ErrUnsupportedByFirmware) {
+				return reading, telegramErr
+			}
+		}
+		if f, ok := api.Requester.(basicStatusFallbacker); ok && f.BasicStatusFallback() {
+			return api.meterReadingFromBasicStatus(ctx)
+		}
+		return MeterReadingResponse{}, err
+	}
+	reading, err := firstMeterReading(res)
+	if err != nil {
+		return reading, err
+	}
+	api.locate(&reading)
+	return reading, nil
+}
+
+// ConnectedUtilities requests the MeterReadingResponse and returns the
+// utilities which are actually reporting to the device, see
+// MeterReadingResponse.ConnectedUtilities.
+func (api *apiRequester) ConnectedUtilities(ctx context.Context) ([]Utility, error) {
+	reading, err := api.GetMeterReading(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return reading.ConnectedUtilities(), nil
+}
+
+// ConnectedUtilities returns the utilities which have a reading. Electricity
+// is connected when the reading has a timestamp or any meter value, S0, Gas
+// and Water are connected when their reading has a timestamp.
+func (r MeterReadingResponse) ConnectedUtilities() []Utility {
+	res := make([]Utility, 0, 4)
+	if r.Timestamp != 0 || r.NetElectricity != 0 || r.Power != 0 ||
+		r.ElectricityImport1 != 0 || r.ElectricityImport2 != 0 ||
+		r.ElectricityExport1 != 0 || r.ElectricityExport2 != 0 {
+		res = append(res, Electricity)
+	}
+	if r.S0Timestamp != 0 {
+		res = append(res, S0)
+	}
+	if r.GasReading.HasReading() {
+		res = append(res, Gas)
+	}
+	if r.WaterReading.HasReading() {
+		res = append(res, Water)
+	}
+	return res
+}
+
+func (api *apiRequester) meterReadingFromTelegram(ctx context.Context) (MeterReadingResponse, error) {
+	telegram, err := api.GetP1Telegram(ctx)
+	if err != nil {
+		return MeterReadingResponse{}, err
+	}
+	parsed, err := telegram.Parse()
+	if err != nil {
 		return MeterReadingResponse{}, err
 	}
-	return res[0], nil
+
+	reading := MeterReadingFromTelegram(parsed)
+	api.locate(&reading)
+	return reading, nil
+}
+
+// locate sets the location of the Requester, when known, on the gas and water
+// readings of r.
+func (api *apiRequester) locate(r *MeterReadingResponse) {
+	loc := api.location()
+	r.GasReading.loc = loc
+	r.WaterReading.loc = loc
+}
+
+func (api *apiRequester) meterReadingFromBasicStatus(ctx context.Context) (MeterReadingResponse, error) {
+	status, err := api.GetBasicStatus(ctx)
+	if err != nil {
+		return MeterReadingResponse{}, err
+	}
+	return MeterReadingFromBasicStatus(status), nil
+}
+
+// telegramFallbacker is implemented by Requesters which can be configured to
+// derive a meter reading from the P1 telegram, e.g. Client.
+type telegramFallbacker interface {
+	TelegramFallback() bool
+}
+
+// basicStatusFallbacker is implemented by Requesters which can be configured
+// to derive a meter reading from the basic status, e.g. Client.
+type basicStatusFallbacker interface {
+	BasicStatusFallback() bool
+}
+
+// locator is implemented by Requesters which know the location of the
+// device's local time, e.g. Client.
+type locator interface {
+	Location() *time.Location
+}
+
+// location returns the location of the device's local time as known by the
+// Requester, or nil when unknown. All device local timestamps of responses
+// are interpreted in this location, or time.Local when it is nil.
+func (api *apiRequester) location() *time.Location {
+	if l, ok := api.Requester.(locator); ok {
+		return l.Location()
+	}
+	return nil
 }
 
 // Time returns Timestamp as time.Time.
@@ -82,3 +299,92 @@ func (r ElectricityReading) Time() time.Time { return time.Unix(r.Timestamp, 0)
 
 // Time returns S0Timestamp as time.Time.
 func (r S0Reading) Time() time.Time { return time.Unix(r.S0Timestamp, 0) }
+
+// PowerKW returns Power in kW.
+func (r ElectricityReading) PowerKW() float64 { return float64(r.Power) / 1000 }
+
+// NetElectricityWh returns NetElectricity in Wh.
+func (r ElectricityReading) NetElectricityWh() float64 { return r.NetElectricity * 1000 }
+
+// Tariffs of the electricity meter, as reported by PhaseReadingResponse.Tariff
+// and inferred by ElectricityReading.CurrentTariff.
+const (
+	TariffLow  uint8 = 1
+	TariffHigh uint8 = 2
+)
+
+// CurrentTariff infers the active tariff by comparing r with an earlier
+// reading prev. The meter reading page does not report the active tariff,
+// but only the counters of the active tariff increase: when only
+// ElectricityImport1 or ElectricityExport1 increased the tariff is
+// TariffLow, when only ElectricityImport2 or ElectricityExport2 increased it
+// is TariffHigh. It reports false when no counter, or counters of both
+// tariffs, increased, e.g. because the readings are too close together or a
+// tariff switch happened in between. Use PhaseReadingResponse.Tariff for the
+// tariff reported by the device itself.
+func (r ElectricityReading) CurrentTariff(prev ElectricityReading) (uint8, bool) {
+	low := r.ElectricityImport1 > prev.ElectricityImport1 ||
+		r.ElectricityExport1 > prev.ElectricityExport1
+	high := r.ElectricityImport2 > prev.ElectricityImport2 ||
+		r.ElectricityExport2 > prev.ElectricityExport2
+
+	switch {
+	case low && !high:
+		return TariffLow, true
+	case high && !low:
+		return TariffHigh, true
+	default:
+		return 0, false
+	}
+}
+
+// readingEpsilon is the maximum difference between two float values of a
+// MeterReadingResponse which are still considered equal. The device reports
+// its totals with three decimals, jitter of one unit in the last decimal is
+// ignored.
+const readingEpsilon = 0.0015
+
+// Equal reports whether r and other contain the same readings. Float values
+// are compared with a small margin, see Diff.
+func (r MeterReadingResponse) Equal(other MeterReadingResponse) bool {
+	return len(r.Diff(other)) == 0
+}
+
+// Diff returns the names of the fields which differ between r and other. Float
+// values which differ no more than one unit in their last (third) decimal are
+// considered equal.
+func (r MeterReadingResponse) Diff(other MeterReadingResponse) []string {
+	var res []string
+	add := func(name string, changed bool) {
+		if changed {
+			res = append(res, name)
+		}
+	}
+	float := func(name string, a, b float64) {
+		add(name, math.Abs(a-b) > readingEpsilon)
+	}
+
+	add("Timestamp", r.Timestamp != other.Timestamp)
+	float("ElectricityImport1", r.ElectricityImport1, other.ElectricityImport1)
+	float("ElectricityImport2", r.ElectricityImport2, other.ElectricityImport2)
+	float("ElectricityExport1", r.ElectricityExport1, other.ElectricityExport1)
+	float("ElectricityExport2", r.ElectricityExport2, other.ElectricityExport2)
+	float("NetElectricity", r.NetElectricity, other.NetElectricity)
+	add("Power", r.Power != other.Power)
+	add("S0Timestamp", r.S0Timestamp != other.S0Timestamp)
+	float("S0Total", r.S0Total, other.S0Total)
+	add("S0", r.S0 != other.S0)
+	add("GasTimestamp", r.GasTimestamp != other.GasTimestamp)
+	float("GasTotal", r.GasTotal, other.GasTotal)
+	add("WaterTimestamp", r.WaterTimestamp != other.WaterTimestamp)
+	float("WaterTotal", r.WaterTotal, other.WaterTotal)
+
+	s0, ok := r.SecondaryS0()
+	otherS0, otherOk := other.SecondaryS0()
+	add("SecondaryS0", ok != otherOk ||
+		s0.S0Timestamp != otherS0.S0Timestamp ||
+		s0.S0 != otherS0.S0 ||
+		math.Abs(s0.S0Total-otherS0.S0Total) > readingEpsilon,
+	)
+	return res
+}
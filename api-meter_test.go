@@ -3,3 +3,227 @@
 // license that can be found in the LICENSE file.
 
 package youless
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type requesterFunc func(ctx context.Context, path string, out any) error
+
+func (fn requesterFunc) Request(ctx context.Context, path string, out any) error {
+	return fn(ctx, path, out)
+}
+
+func TestAPIRequester_GetMeterReading(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		api := NewAPIRequester(requesterFunc(func(_ context.Context, path string, out any) error {
+			assert.Equal(t, "e", path)
+			return json.Unmarshal([]byte(`[{"tm":1706443200,"net":1234.567,"pwr":350}]`), out)
+		}))
+
+		have, err := api.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1706443200), have.Timestamp)
+		assert.Equal(t, 1234.567, have.NetElectricity)
+		assert.Equal(t, int64(350), have.Power)
+	})
+	t.Run("empty", func(t *testing.T) {
+		api := NewAPIRequester(requesterFunc(func(_ context.Context, _ string, out any) error {
+			return json.Unmarshal([]byte(`[]`), out)
+		}))
+
+		_, err := api.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, ErrEmptyResponse)
+	})
+}
+
+func TestAPIRequester_ConnectedUtilities(t *testing.T) {
+	tests := map[string]struct {
+		data string
+		want []Utility
+	}{
+		"electricity only": {
+			data: `[{"tm":1706443200,"net":1234.567,"pwr":350,"gts":0,"gas":0,"wts":0,"wtr":0}]`,
+			want: []Utility{Electricity},
+		},
+		"all": {
+			data: `[{"tm":1706443200,"pwr":350,"ts0":1706443200,"cs0":1.5,"gts":2401281200,"gas":456.789,"wts":2401281200,"wtr":12.3}]`,
+			want: []Utility{Electricity, S0, Gas, Water},
+		},
+		"gas without electricity": {
+			data: `[{"gts":2401281200,"gas":456.789}]`,
+			want: []Utility{Gas},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := NewAPIRequester(requesterFunc(func(_ context.Context, _ string, out any) error {
+				return json.Unmarshal([]byte(tc.data), out)
+			}))
+
+			have, err := api.(*apiRequester).ConnectedUtilities(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestMeterReadingResponse_Diff(t *testing.T) {
+	a := MeterReadingResponse{
+		ElectricityReading: ElectricityReading{
+			Timestamp:          1706443200,
+			ElectricityImport1: 1000.123,
+			Power:              350,
+		},
+		GasReading: GasReading{GasTotal: 456.789},
+	}
+
+	t.Run("equal", func(t *testing.T) {
+		assert.True(t, a.Equal(a))
+		assert.Empty(t, a.Diff(a))
+	})
+	t.Run("jitter", func(t *testing.T) {
+		b := a
+		b.ElectricityImport1 = 1000.124
+		b.GasTotal = 456.788
+		assert.True(t, a.Equal(b))
+	})
+	t.Run("changed", func(t *testing.T) {
+		b := a
+		b.ElectricityImport1 = 1000.125
+		b.Power = 360
+		b.WaterTimestamp = 2401281200
+		assert.False(t, a.Equal(b))
+		assert.Equal(t, []string{"ElectricityImport1", "Power", "WaterTimestamp"}, a.Diff(b))
+	})
+}
+
+func TestMeterReadingResponse_SecondaryS0(t *testing.T) {
+	t.Run("absent", func(t *testing.T) {
+		var have MeterReadingResponse
+		assert.NoError(t, json.Unmarshal([]byte(`{"ts0":1706443200,"cs0":12.345,"ps0":0}`), &have))
+		assert.Equal(t, 12.345, have.S0Total)
+
+		s0, ok := have.SecondaryS0()
+		assert.False(t, ok)
+		assert.Equal(t, S0Reading{}, s0)
+	})
+	t.Run("present", func(t *testing.T) {
+		var have MeterReadingResponse
+		assert.NoError(t, json.Unmarshal([]byte(`{"cs0":12.345,"cs1":0,"ps1":0}`), &have))
+
+		s0, ok := have.SecondaryS0()
+		assert.True(t, ok)
+		assert.Equal(t, S0Reading{}, s0)
+	})
+}
+
+func TestMeterReadingResponse_UnmarshalJSON(t *testing.T) {
+	var have MeterReadingResponse
+	assert.NoError(t, json.Unmarshal([]byte(`{"tm":1706443200,"pwr":"1.234,5","p1":"1.000,123","p2":1200.456,"gas":""}`), &have))
+	assert.Equal(t, int64(1706443200), have.Timestamp)
+	assert.Equal(t, int64(1235), have.Power)
+	assert.Equal(t, 1000.123, have.ElectricityImport1)
+	assert.Equal(t, 1200.456, have.ElectricityImport2)
+	assert.Equal(t, 0.0, have.GasTotal)
+
+	t.Run("invalid", func(t *testing.T) {
+		var have MeterReadingResponse
+		assert.Error(t, json.Unmarshal([]byte(`{"pwr":"abc"}`), &have))
+	})
+	t.Run("unknown string field", func(t *testing.T) {
+		var have MeterReadingResponse
+		assert.NoError(t, json.Unmarshal([]byte(`{"pwr":350,"p1":"1,5","sts":"ok"}`), &have))
+		assert.Equal(t, int64(350), have.Power)
+		assert.Equal(t, 1.5, have.ElectricityImport1)
+	})
+}
+
+func TestMeterReadingResponse_MarshalJSON(t *testing.T) {
+	t.Run("single S0", func(t *testing.T) {
+		var want MeterReadingResponse
+		assert.NoError(t, json.Unmarshal([]byte(`{"tm":1706443200,"pwr":350,"cs0":1.5}`), &want))
+
+		data, err := json.Marshal(want)
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "ts1")
+
+		var have MeterReadingResponse
+		assert.NoError(t, json.Unmarshal(data, &have))
+		assert.Equal(t, want, have)
+	})
+	t.Run("dual S0", func(t *testing.T) {
+		var want MeterReadingResponse
+		assert.NoError(t, json.Unmarshal([]byte(`{"tm":1706443200,"cs0":1.5,"ts1":1706443200,"cs1":6.789,"ps1":120}`), &want))
+
+		data, err := json.Marshal(want)
+		assert.NoError(t, err)
+
+		var have MeterReadingResponse
+		assert.NoError(t, json.Unmarshal(data, &have))
+		assert.Equal(t, want, have)
+
+		s0, ok := have.SecondaryS0()
+		assert.True(t, ok)
+		assert.Equal(t, 6.789, s0.S0Total)
+	})
+}
+
+func TestElectricityReading_CurrentTariff(t *testing.T) {
+	prev := ElectricityReading{
+		ElectricityImport1: 1000.123,
+		ElectricityImport2: 1200.456,
+		ElectricityExport1: 400.001,
+		ElectricityExport2: 566.011,
+	}
+
+	tests := map[string]struct {
+		change func(r *ElectricityReading)
+		want   uint8
+		wantOk bool
+	}{
+		"low import": {
+			change: func(r *ElectricityReading) { r.ElectricityImport1 += 0.002 },
+			want:   TariffLow,
+			wantOk: true,
+		},
+		"high import": {
+			change: func(r *ElectricityReading) { r.ElectricityImport2 += 0.002 },
+			want:   TariffHigh,
+			wantOk: true,
+		},
+		"low export": {
+			change: func(r *ElectricityReading) { r.ElectricityExport1 += 0.05 },
+			want:   TariffLow,
+			wantOk: true,
+		},
+		"high export": {
+			change: func(r *ElectricityReading) { r.ElectricityExport2 += 0.05 },
+			want:   TariffHigh,
+			wantOk: true,
+		},
+		"unchanged": {
+			change: func(r *ElectricityReading) {},
+		},
+		"tariff switch": {
+			change: func(r *ElectricityReading) {
+				r.ElectricityImport1 += 0.001
+				r.ElectricityImport2 += 0.001
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := prev
+			tc.change(&r)
+
+			have, ok := r.CurrentTariff(prev)
+			assert.Equal(t, tc.want, have)
+			assert.Equal(t, tc.wantOk, ok)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package youless
 
 import (
 	"context"
+	"math"
 )
 
 // https://community.home-assistant.io/t/youless-sensors-for-detailed-information-per-phase/433419
@@ -16,30 +17,30 @@ type PhaseReadingResponse struct {
 
 	// Current1 is the current imported electricity current in Ampere on phase 1
 	// (Stroom L1).
-	Current1 float64 `json:"i1"`
+	Current1 float64 `json:"i1" unit:"A"`
 	// Current2 is the current imported electricity current in Ampere on phase 2
 	// (Stroom L2).
-	Current2 float64 `json:"i2"`
+	Current2 float64 `json:"i2" unit:"A"`
 	// Current3 is the current imported electricity current in Ampere on phase 3
 	// (Stroom L3).
-	Current3 float64 `json:"i3"`
+	Current3 float64 `json:"i3" unit:"A"`
 
 	// Power1 is the current imported electricity power in Watt on phase 1
 	// (Vermogen L1).
-	Power1 int64 `json:"l1"`
+	Power1 int64 `json:"l1" unit:"W"`
 	// Power2 is the current imported electricity power in Watt on phase 2
 	// (Vermogen L2).
-	Power2 int64 `json:"l2"`
+	Power2 int64 `json:"l2" unit:"W"`
 	// Power3 is the current imported electricity power in Watt on phase 3
 	// (Vermogen L3).
-	Power3 int64 `json:"l3"`
+	Power3 int64 `json:"l3" unit:"W"`
 
 	// Voltage1 is the current measured voltage on phase 1 (Spanning L1).
-	Voltage1 float64 `json:"v1"`
+	Voltage1 float64 `json:"v1" unit:"V"`
 	// Voltage2 is the current measured voltage on phase 2 (Spanning L2).
-	Voltage2 float64 `json:"v2"`
+	Voltage2 float64 `json:"v2" unit:"V"`
 	// Voltage3 is the current measured voltage on phase 3 (Spanning L3).
-	Voltage3 float64 `json:"v3"`
+	Voltage3 float64 `json:"v3" unit:"V"`
 }
 
 func (api *apiRequester) GetPhaseReading(ctx context.Context) (PhaseReadingResponse, error) {
@@ -62,7 +63,25 @@ type PhaseReading struct {
 
 // InUse indicates if the phase is in use or not.
 func (r PhaseReading) InUse() bool {
-	return r.Current == 0 && r.Power == 0
+	return r.Current != 0 || r.Power != 0
+}
+
+// ApparentPower returns the apparent power in volt-ampere (VA), which is the
+// product of Voltage and Current. It returns 0 when either is 0.
+func (r PhaseReading) ApparentPower() float64 {
+	return r.Voltage * math.Abs(r.Current)
+}
+
+// PowerFactor returns the ratio between the real Power and ApparentPower,
+// ranging from 0 to 1. Exported power results in the same factor as imported
+// power. It returns 0 when the ApparentPower is 0. Because the device does not
+// measure all values at exactly the same moment, the factor is capped at 1.
+func (r PhaseReading) PowerFactor() float64 {
+	va := r.ApparentPower()
+	if va == 0 {
+		return 0
+	}
+	return min(math.Abs(float64(r.Power))/va, 1)
 }
 
 // Phase1 returns a PhaseReading of phase 1.
@@ -91,3 +110,45 @@ func (r PhaseReadingResponse) Phase3() PhaseReading {
 		Voltage: r.Voltage3,
 	}
 }
+
+// ActivePhases returns the PhaseReading of all phases which are in use.
+func (r PhaseReadingResponse) ActivePhases() []PhaseReading {
+	res := make([]PhaseReading, 0, 3)
+	for _, p := range [...]PhaseReading{r.Phase1(), r.Phase2(), r.Phase3()} {
+		if p.InUse() {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// BalanceThreshold is the maximum difference in Ampere between the highest
+// and lowest current of the active phases for the load to be considered
+// balanced.
+const BalanceThreshold = 2.0
+
+// TotalPower returns the sum of the power in Watt of all phases.
+func (r PhaseReadingResponse) TotalPower() int64 {
+	return r.Power1 + r.Power2 + r.Power3
+}
+
+// TotalCurrent returns the sum of the current in Ampere of all phases.
+func (r PhaseReadingResponse) TotalCurrent() float64 {
+	return r.Current1 + r.Current2 + r.Current3
+}
+
+// BalancedLoad indicates if the difference between the highest and lowest
+// current of the active phases is within BalanceThreshold. A reading with
+// less than two active phases is always considered balanced.
+func (r PhaseReadingResponse) BalancedLoad() bool {
+	phases := r.ActivePhases()
+	if len(phases) < 2 {
+		return true
+	}
+
+	lo, hi := phases[0].Current, phases[0].Current
+	for _, p := range phases[1:] {
+		lo, hi = min(lo, p.Current), max(hi, p.Current)
+	}
+	return hi-lo <= BalanceThreshold
+}
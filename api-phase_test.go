@@ -0,0 +1,98 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPhaseReading_InUse(t *testing.T) {
+	assert.False(t, PhaseReading{Voltage: 230}.InUse())
+	assert.True(t, PhaseReading{Current: 1.5, Voltage: 230}.InUse())
+	assert.True(t, PhaseReading{Power: 350, Voltage: 230}.InUse())
+}
+
+func TestPhaseReading_ApparentPower(t *testing.T) {
+	assert.InDelta(t, 351.424, PhaseReading{Current: 1.52, Voltage: 231.2}.ApparentPower(), 0.0001)
+	assert.InDelta(t, 351.424, PhaseReading{Current: -1.52, Voltage: 231.2}.ApparentPower(), 0.0001)
+	assert.Equal(t, 0.0, PhaseReading{Voltage: 230}.ApparentPower())
+	assert.Equal(t, 0.0, PhaseReading{Current: 1.5}.ApparentPower())
+}
+
+func TestPhaseReading_PowerFactor(t *testing.T) {
+	tests := map[string]struct {
+		reading PhaseReading
+		want    float64
+	}{
+		"import":       {reading: PhaseReading{Current: 1.52, Power: 320, Voltage: 231.2}, want: 0.9106},
+		"export":       {reading: PhaseReading{Current: 1.52, Power: -320, Voltage: 231.2}, want: 0.9106},
+		"capped":       {reading: PhaseReading{Current: 1, Power: 240, Voltage: 230}, want: 1},
+		"zero current": {reading: PhaseReading{Power: 350, Voltage: 230}, want: 0},
+		"zero voltage": {reading: PhaseReading{Current: 1.5, Power: 350}, want: 0},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, tc.reading.PowerFactor(), 0.0001)
+		})
+	}
+}
+
+func TestPhaseReadingResponse_ActivePhases(t *testing.T) {
+	t.Run("single phase", func(t *testing.T) {
+		r := PhaseReadingResponse{Current1: 1.52, Power1: 350, Voltage1: 231.2}
+		assert.Equal(t, []PhaseReading{r.Phase1()}, r.ActivePhases())
+	})
+	t.Run("three phases", func(t *testing.T) {
+		r := PhaseReadingResponse{Power1: 350, Power2: 120, Current3: 0.2}
+		assert.Equal(t, []PhaseReading{r.Phase1(), r.Phase2(), r.Phase3()}, r.ActivePhases())
+	})
+	t.Run("none", func(t *testing.T) {
+		assert.Empty(t, PhaseReadingResponse{}.ActivePhases())
+	})
+}
+
+func TestPhaseReadingResponse_TotalPower(t *testing.T) {
+	r := PhaseReadingResponse{Power1: 350, Power2: 120, Power3: -80}
+	assert.Equal(t, int64(390), r.TotalPower())
+}
+
+func TestPhaseReadingResponse_TotalCurrent(t *testing.T) {
+	r := PhaseReadingResponse{Current1: 1.5, Current2: 0.5, Current3: 0.25}
+	assert.InDelta(t, 2.25, r.TotalCurrent(), 0.0001)
+}
+
+func TestPhaseReadingResponse_BalancedLoad(t *testing.T) {
+	tests := map[string]struct {
+		reading PhaseReadingResponse
+		want    bool
+	}{
+		"none": {
+			want: true,
+		},
+		"single phase": {
+			reading: PhaseReadingResponse{Current1: 12, Power1: 2800},
+			want:    true,
+		},
+		"balanced": {
+			reading: PhaseReadingResponse{Current1: 4.2, Current2: 3.1, Current3: 2.5},
+			want:    true,
+		},
+		"unbalanced": {
+			reading: PhaseReadingResponse{Current1: 9.8, Current2: 3.1, Current3: 2.5},
+			want:    false,
+		},
+		"inactive phase ignored": {
+			reading: PhaseReadingResponse{Current1: 9.8, Current2: 9.1, Voltage3: 230},
+			want:    true,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.reading.BalancedLoad())
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetSettings retrieves all settings from the device's settings page as raw
+// key-value pairs. This page requires authentication when the device is
+// password protected. The available keys and the format of their values
+// depend on the device's firmware, all values are returned as strings as
+// formatted in the page's json.
+func (api *apiRequester) GetSettings(ctx context.Context) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := api.Request(withFuncName(ctx, "GetSettings"), "S?f=j", &raw); err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(raw))
+	for key, val := range raw {
+		var s string
+		if err := json.Unmarshal(val, &s); err == nil {
+			res[key] = s
+			continue
+		}
+		// numbers, booleans and any other values are kept as they appear
+		res[key] = string(val)
+	}
+	return res, nil
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_GetSettings(t *testing.T) {
+	api := NewAPIRequester(requesterFunc(func(_ context.Context, path string, out any) error {
+		assert.Equal(t, "S?f=j", path)
+		return json.Unmarshal([]byte(`{"ppk":1000,"off":12.5,"mtr":"P1","dhcp":true,"ip":"192.168.1.10"}`), out)
+	}))
+
+	have, err := api.GetSettings(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"ppk":  "1000",
+		"off":  "12.5",
+		"mtr":  "P1",
+		"dhcp": "true",
+		"ip":   "192.168.1.10",
+	}, have)
+}
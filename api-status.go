@@ -0,0 +1,142 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+// BasicStatusResponse is the response from the /a endpoint. This endpoint is
+// also available on older (non-enologic) firmware.
+type BasicStatusResponse struct {
+	// Count is the meter reading of total electricity in kWh (Meterstand).
+	Count float64 `json:"cnt" unit:"kWh"`
+	// Power is the current electricity power in Watt (Actueel vermogen).
+	Power int64 `json:"pwr" unit:"W"`
+	// Level is the signal level of the antenna in percentages.
+	Level int `json:"lvl" unit:"%"`
+	// Deviation is the deviation of the signal level.
+	Deviation string `json:"dev"`
+	// Connection is the status of the connection with the meter.
+	Connection string `json:"con"`
+	// Status is the current status of the device.
+	Status string `json:"sts"`
+	// Raw is the raw value of the sensor.
+	Raw int64 `json:"raw"`
+}
+
+func (api *apiRequester) GetBasicStatus(ctx context.Context) (BasicStatusResponse, error) {
+	var res BasicStatusResponse
+	if err := api.Request(withFuncName(ctx, "GetBasicStatus"), "a?f=j", &res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// MeterReadingFromBasicStatus maps the BasicStatusResponse to a minimal
+// MeterReadingResponse, so legacy devices without the meter reading page (e.g.
+// a LS110 with non-enologic firmware) can be used like newer devices. Count is
+// mapped to NetElectricity and Power to Power. All other fields are not
+// available on legacy firmware and are left empty; this includes Timestamp,
+// the import and export meter readings per tariff, S0, gas and water.
+// Legacy firmware does not support phase readings either.
+func MeterReadingFromBasicStatus(s BasicStatusResponse) MeterReadingResponse {
+	var res MeterReadingResponse
+	res.NetElectricity = s.Count
+	res.Power = s.Power
+	return res
+}
+
+// UnmarshalJSON unmarshals the json data into BasicStatusResponse. It converts
+// the European formatted count (e.g. "1.234,567") to a float64.
+func (r *BasicStatusResponse) UnmarshalJSON(data []byte) error {
+	type alias BasicStatusResponse
+	var v struct {
+		alias
+		Count string `json:"cnt"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.WithStack(err)
+	}
+
+	*r = BasicStatusResponse(v.alias)
+	if v.Count == "" {
+		return nil
+	}
+
+	n, err := parseDecimal(v.Count)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	r.Count = n
+	return nil
+}
+
+// parseDecimal parses a European formatted decimal number, which uses a comma
+// as decimal separator and dots as thousands separator, to a float64.
+func parseDecimal(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if strings.ContainsRune(s, ',') {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// normalizeDecimals replaces the string values of keys in the json object in
+// data with json numbers, which are parsed using parseDecimal. Values of keys
+// with value true are rounded to the nearest integer. String values of any
+// other keys are left untouched. Data is returned as is when none of the
+// values of keys are strings.
+func normalizeDecimals(data []byte, keys map[string]bool) ([]byte, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var changed bool
+	for key, raw := range obj {
+		isInt, ok := keys[key]
+		if !ok || len(raw) == 0 || raw[0] != '"' {
+			continue
+		}
+
+		var str string
+		if err := json.Unmarshal(raw, &str); err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if str == "" {
+			delete(obj, key)
+			changed = true
+			continue
+		}
+
+		n, err := parseDecimal(str)
+		if err != nil {
+			return nil, errors.Wrapf(err, "key %q", key)
+		}
+		if isInt {
+			obj[key] = json.RawMessage(strconv.FormatInt(int64(math.Round(n)), 10))
+		} else {
+			obj[key] = json.RawMessage(strconv.FormatFloat(n, 'f', -1, 64))
+		}
+		changed = true
+	}
+	if !changed {
+		return data, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBasicStatusResponse_UnmarshalJSON(t *testing.T) {
+	tests := map[string]float64{
+		`{"cnt":" 1234,567","pwr":350,"lvl":90}`: 1234.567,
+		`{"cnt":"1.234,567","pwr":350,"lvl":90}`: 1234.567,
+		`{"cnt":"1234.567","pwr":350,"lvl":90}`:  1234.567,
+	}
+	for data, want := range tests {
+		t.Run(data, func(t *testing.T) {
+			var have BasicStatusResponse
+			assert.NoError(t, json.Unmarshal([]byte(data), &have))
+			assert.Equal(t, want, have.Count)
+			assert.Equal(t, int64(350), have.Power)
+			assert.Equal(t, 90, have.Level)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		var have BasicStatusResponse
+		assert.Error(t, json.Unmarshal([]byte(`{"cnt":"abc"}`), &have))
+	})
+}
+
+func TestMeterReadingFromBasicStatus(t *testing.T) {
+	have := MeterReadingFromBasicStatus(BasicStatusResponse{
+		Count: 1234.567,
+		Power: 350,
+		Level: 90,
+	})
+	assert.Equal(t, 1234.567, have.NetElectricity)
+	assert.Equal(t, int64(350), have.Power)
+	assert.Equal(t, []Utility{Electricity}, have.ConnectedUtilities())
+}
+
+func TestWithBasicStatusFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/e":
+			w.WriteHeader(http.StatusNotFound)
+		case "/a":
+			_, _ = w.Write([]byte(`{"cnt":" 1234,567","pwr":350,"lvl":90,"dev":"","det":"","con":"","sts":"","raw":0}`))
+		default:
+			t.Fatalf("unexpected request %s", r.URL)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("enabled", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithBasicStatusFallback())
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1234.567, have.NetElectricity)
+		assert.Equal(t, int64(350), have.Power)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, ErrUnsupportedByFirmware)
+	})
+}
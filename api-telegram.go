@@ -5,8 +5,11 @@
 package youless
 
 import (
+	"bytes"
 	"context"
 	"strconv"
+
+	"github.com/go-pogo/errors"
 )
 
 // P1TelegramResponse contains a raw PI telegram response from the
@@ -15,37 +18,87 @@ type P1TelegramResponse struct {
 	Data []byte
 }
 
+// DefaultTelegramMaxPages is the default maximum number of pages
+// GetP1Telegram requests before giving up on finding the telegram's end.
+const DefaultTelegramMaxPages = 5
+
+const (
+	ErrTelegramIncomplete      errors.Msg = "end of P1 telegram not found"
+	ErrInvalidTelegramMaxPages errors.Msg = "max pages must be positive"
+)
+
+// GetP1Telegram retrieves the P1 telegram, which may span multiple pages. The
+// timeout applies to all pages combined, instead of to each separate page.
+// When the end of the telegram is not found within the maximum number of
+// pages (see WithTelegramMaxPages), the data received so far is returned
+// together with an ErrTelegramIncomplete error.
 func (api *apiRequester) GetP1Telegram(ctx context.Context) (P1TelegramResponse, error) {
 	var res P1TelegramResponse
 	var buf []byte
 
-	var atEnd bool
-	for i := 1; i <= 3 || !atEnd; i++ {
+	maxPages := DefaultTelegramMaxPages
+	if p, ok := api.Requester.(telegramPager); ok && p.TelegramMaxPages() > 0 {
+		maxPages = p.TelegramMaxPages()
+	}
+
+	ctx = withTimeoutBudget(ctx)
+
+	for i := 1; i <= maxPages; i++ {
 		if err := api.Request(withFuncName(ctx, "GetP1Telegram"), "V?p="+strconv.Itoa(i), &buf); err != nil {
 			return res, err
 		}
 		if len(buf) == 0 {
-			break
-		}
-		if len(buf) >= 7 {
-			atEnd = buf[len(buf)-7] == '!'
-			if atEnd && i == 1 {
-				// take the buffer when at end of first page,
-				// there is no need to reset and copy it
-				// because there will be no more data
-				res.Data = buf
-				break
-			}
+			// no more data
+			return res, nil
 		}
 
-		if res.Data == nil {
-			res.Data = make([]byte, len(buf))
+		atEnd := telegramEnd(buf)
+		if atEnd && i == 1 {
+			// take the buffer when at end of first page,
+			// there is no need to reset and copy it
+			// because there will be no more data
+			res.Data = buf
+			return res, nil
 		}
 
 		// copy data to result and reset buffer for next request
 		res.Data = append(res.Data, buf...)
 		buf = buf[:0]
+
+		if atEnd {
+			return res, nil
+		}
 	}
 
-	return res, nil
+	return res, errors.Wrapf(ErrTelegramIncomplete, "after %d pages", maxPages)
+}
+
+// telegramPager is implemented by Requesters which can be configured with a
+// maximum number of P1 telegram pages, e.g. Client.
+type telegramPager interface {
+	TelegramMaxPages() int
+}
+
+// telegramEnd reports whether b ends with the end of a P1 telegram, which is
+// a line with a "!" followed by an optional 4 character hexadecimal CRC.
+// Trailing whitespace and both "\r\n" and "\n" line endings are accepted.
+func telegramEnd(b []byte) bool {
+	b = bytes.TrimRight(b, " \t\r\n\x00")
+	if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+		b = b[i+1:]
+	}
+
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 || b[0] != '!' {
+		return false
+	}
+	crc := b[1:]
+	if len(crc) == 0 {
+		return true
+	}
+	if len(crc) != 4 {
+		return false
+	}
+	_, err := strconv.ParseUint(string(crc), 16, 16)
+	return err == nil
 }
@@ -0,0 +1,104 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_GetP1Telegram(t *testing.T) {
+	pages := func(pages ...string) (Requester, *[]string) {
+		var calls []string
+		return requesterFunc(func(_ context.Context, path string, out any) error {
+			calls = append(calls, path)
+			var i int
+			_, _ = fmt.Sscanf(path, "V?p=%d", &i)
+			if i <= len(pages) {
+				*out.(*[]byte) = append((*out.(*[]byte))[:0], pages[i-1]...)
+			} else {
+				*out.(*[]byte) = (*out.(*[]byte))[:0]
+			}
+			return nil
+		}), &calls
+	}
+
+	t.Run("single page", func(t *testing.T) {
+		r, calls := pages(testTelegram)
+		have, err := NewAPIRequester(r).GetP1Telegram(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testTelegram, string(have.Data))
+		assert.Equal(t, []string{"V?p=1"}, *calls)
+	})
+	t.Run("multiple pages", func(t *testing.T) {
+		half := len(testTelegram) / 2
+		r, calls := pages(testTelegram[:half], testTelegram[half:])
+		have, err := NewAPIRequester(r).GetP1Telegram(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, testTelegram, string(have.Data))
+		assert.Equal(t, []string{"V?p=1", "V?p=2"}, *calls)
+	})
+	t.Run("no end marker", func(t *testing.T) {
+		var calls int
+		r := requesterFunc(func(_ context.Context, _ string, out any) error {
+			calls++
+			*out.(*[]byte) = append((*out.(*[]byte))[:0], "1-0:1.8.1(001000.123*kWh)\r\n"...)
+			return nil
+		})
+
+		have, err := NewAPIRequester(r).GetP1Telegram(context.Background())
+		assert.ErrorIs(t, err, ErrTelegramIncomplete)
+		assert.Equal(t, DefaultTelegramMaxPages, calls)
+		assert.Equal(t, strings.Repeat("1-0:1.8.1(001000.123*kWh)\r\n", DefaultTelegramMaxPages), string(have.Data))
+	})
+}
+
+func TestWithTelegramMaxPages(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte("1-0:1.8.1(001000.123*kWh)\r\n"))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL}, WithTelegramMaxPages(2))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, c.TelegramMaxPages())
+
+	_, err = c.GetP1Telegram(context.Background())
+	assert.ErrorIs(t, err, ErrTelegramIncomplete)
+	assert.Equal(t, int32(2), calls.Load())
+
+	_, err = NewClient(Config{BaseURL: "http://youless"}, WithTelegramMaxPages(0))
+	assert.ErrorIs(t, err, ErrInvalidTelegramMaxPages)
+}
+
+func TestTelegramEnd(t *testing.T) {
+	tests := map[string]bool{
+		"!1A2B\r\n":                     true,
+		"!1A2B\n":                       true,
+		"!1A2B":                         true,
+		"!1a2b\r\n\r\n  ":               true,
+		"!\r\n":                         true,
+		"(00.350*kW)\r\n!1A2B\r\n":      true,
+		"(00.350*kW)\n!1A2B\n\n\x00":    true,
+		"1-0:1.8.1(001000.123*kWh)\r\n": false,
+		"!1A2\r\n":                      false,
+		"!XYZW\r\n":                     false,
+		"":                              false,
+	}
+	for input, want := range tests {
+		t.Run(strings.NewReplacer("\r", `\r`, "\n", `\n`, "\x00", `\0`).Replace(input), func(t *testing.T) {
+			assert.Equal(t, want, telegramEnd([]byte(input)))
+		})
+	}
+}
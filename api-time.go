@@ -0,0 +1,38 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrNoDeviceTimestamp errors.Msg = "meter reading has no timestamp"
+
+// ClockSkew estimates the difference between the clock of the YouLess device
+// and the host's clock, by comparing the timestamp of the meter reading to the
+// midpoint of the request's round trip. A positive duration means the
+// device's clock is ahead of the host's clock, a negative duration means it
+// is behind.
+// Note: the timestamp has a resolution of one second and is the time of the
+// last reading of the meter, which may lag slightly behind the device's clock.
+// Readings reused from an earlier request, see WithGroupWindow, skew the
+// estimate.
+func (api *apiRequester) ClockSkew(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	reading, err := api.GetMeterReading(ctx)
+	end := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	if reading.Timestamp == 0 {
+		return 0, errors.New(ErrNoDeviceTimestamp)
+	}
+
+	mid := start.Add(end.Sub(start) / 2)
+	return reading.ElectricityReading.Time().Sub(mid).Round(time.Second), nil
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_ClockSkew(t *testing.T) {
+	tests := map[string]time.Duration{
+		"behind":  -40 * time.Minute,
+		"ahead":   90 * time.Second,
+		"in sync": 0,
+	}
+	for name, skew := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &apiRequester{requesterFunc(func(_ context.Context, _ string, out any) error {
+				tm := time.Now().Add(skew).Unix()
+				return json.Unmarshal([]byte(fmt.Sprintf(`[{"tm":%d,"pwr":350}]`, tm)), out)
+			})}
+
+			have, err := api.ClockSkew(context.Background())
+			assert.NoError(t, err)
+			assert.InDelta(t, skew, have, float64(time.Second))
+		})
+	}
+	t.Run("no timestamp", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(_ context.Context, _ string, out any) error {
+			return json.Unmarshal([]byte(`[{"pwr":350}]`), out)
+		})}
+
+		_, err := api.ClockSkew(context.Background())
+		assert.ErrorIs(t, err, ErrNoDeviceTimestamp)
+	})
+	t.Run("error", func(t *testing.T) {
+		wantErr := errors.New("some error")
+		api := &apiRequester{requesterFunc(func(context.Context, string, any) error {
+			return wantErr
+		})}
+
+		_, err := api.ClockSkew(context.Background())
+		assert.ErrorIs(t, err, wantErr)
+	})
+}
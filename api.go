@@ -6,16 +6,22 @@ package youless
 
 import (
 	"context"
+	"time"
 )
 
 // API is the interface containing all available api calls to the YouLess
 // device.
 type API interface {
+	GetBasicStatus(ctx context.Context) (BasicStatusResponse, error)
 	GetDeviceInfo(ctx context.Context) (DeviceInfoResponse, error)
 	GetMeterReading(ctx context.Context) (MeterReadingResponse, error)
 	GetPhaseReading(ctx context.Context) (PhaseReadingResponse, error)
 	GetLog(ctx context.Context, u Utility, i Interval, page uint) (LogResponse, error)
+	GetLogPage(ctx context.Context, u Utility, i Interval, page uint) (LogResponse, bool, error)
+	GetLatestLog(ctx context.Context, u Utility, i Interval) (LogResponse, error)
+	GetHistory(ctx context.Context, u Utility, year int, month time.Month) (HistoryResponse, error)
 	GetP1Telegram(ctx context.Context) (P1TelegramResponse, error)
+	GetSettings(ctx context.Context) (map[string]string, error)
 }
 
 // Requester requests and handles calls to a YouLess device.
@@ -0,0 +1,113 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrUnsupportedByFirmware errors.Msg = "unsupported by firmware"
+	ErrInvalidFirmware       errors.Msg = "invalid firmware version"
+)
+
+// Caps describes which api calls are supported by the YouLess device.
+type Caps struct {
+	// HasBasicStatus indicates support for [API.GetBasicStatus].
+	HasBasicStatus bool
+	// HasMeterReading indicates support for [API.GetMeterReading].
+	HasMeterReading bool
+	// HasPhaseReading indicates support for [API.GetPhaseReading].
+	HasPhaseReading bool
+	// HasP1Telegram indicates support for [API.GetP1Telegram].
+	HasP1Telegram bool
+	// HasGasMeter indicates support for [API.GetLog] with the Gas utility.
+	HasGasMeter bool
+	// HasWaterMeter indicates support for [API.GetLog] with the Water utility.
+	HasWaterMeter bool
+	// HasS0Meter indicates support for [API.GetLog] with the S0 utility.
+	HasS0Meter bool
+}
+
+// Capabilities requests the device info and determines the Caps of the device
+// based on its model and firmware. The LS110 only supports the basic status,
+// the LS120 with enologic firmware (suffixed with "-EL") supports all api
+// calls. The phase reading requires at least firmware version 1.5.
+func (api *apiRequester) Capabilities(ctx context.Context) (Caps, error) {
+	info, err := api.GetDeviceInfo(ctx)
+	if err != nil {
+		return Caps{}, err
+	}
+	return capsOf(info)
+}
+
+func capsOf(info DeviceInfoResponse) (Caps, error) {
+	caps := Caps{HasBasicStatus: true}
+	if !strings.HasPrefix(strings.ToUpper(info.Model), "LS120") {
+		return caps, nil
+	}
+
+	major, minor, _, variant, err := parseFirmware(info.Firmware)
+	if err != nil {
+		return caps, err
+	}
+
+	caps.HasS0Meter = true
+	if variant != "EL" {
+		return caps, nil
+	}
+
+	caps.HasMeterReading = true
+	caps.HasP1Telegram = true
+	caps.HasGasMeter = true
+	caps.HasWaterMeter = true
+	caps.HasPhaseReading = major > 1 || (major == 1 && minor >= 5)
+	return caps, nil
+}
+
+// parseFirmware parses a firmware version string like "1.5.1-EL" into its
+// major, minor and patch numbers and variant suffix. Pre-release markers, like
+// the "rc1" in "1.6.0-rc1-EL", are skipped.
+func parseFirmware(fw string) (major, minor, patch int, variant string, err error) {
+	fw = strings.TrimPrefix(strings.TrimSpace(fw), "v")
+	fw, suffix, _ := strings.Cut(fw, "-")
+	for _, s := range strings.Split(suffix, "-") {
+		if s != "" && variant == "" && !isPreRelease(s) {
+			variant = s
+		}
+	}
+
+	parts := strings.Split(fw, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		err = errors.Wrap(errors.Newf("%q", fw), ErrInvalidFirmware)
+		return
+	}
+
+	nums := make([]int, 3)
+	for i, p := range parts {
+		if nums[i], err = strconv.Atoi(p); err != nil {
+			err = errors.Wrap(err, ErrInvalidFirmware)
+			return
+		}
+	}
+	return nums[0], nums[1], nums[2], strings.ToUpper(variant), nil
+}
+
+var preReleaseMarkers = []string{"alpha", "beta", "rc", "pre", "dev"}
+
+// isPreRelease indicates if s is a pre-release marker like "beta" or "rc1".
+func isPreRelease(s string) bool {
+	s = strings.ToLower(s)
+	for _, m := range preReleaseMarkers {
+		if strings.HasPrefix(s, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFirmware(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		major, minor, patch, variant, err := parseFirmware("1.5.1-EL")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 5, 1}, []int{major, minor, patch})
+		assert.Equal(t, "EL", variant)
+	})
+	t.Run("without variant", func(t *testing.T) {
+		major, minor, patch, variant, err := parseFirmware("1.4")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 4, 0}, []int{major, minor, patch})
+		assert.Equal(t, "", variant)
+	})
+	t.Run("pre-release", func(t *testing.T) {
+		major, minor, patch, variant, err := parseFirmware("1.6.0-rc1-el")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 6, 0}, []int{major, minor, patch})
+		assert.Equal(t, "EL", variant)
+
+		_, _, _, variant, err = parseFirmware("1.6.0-beta2")
+		assert.NoError(t, err)
+		assert.Equal(t, "", variant)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, _, _, _, err := parseFirmware("abc")
+		assert.ErrorIs(t, err, ErrInvalidFirmware)
+	})
+}
+
+func TestCapsOf(t *testing.T) {
+	tests := map[string]struct {
+		info DeviceInfoResponse
+		want Caps
+	}{
+		"LS110": {
+			info: DeviceInfoResponse{Model: "LS110", Firmware: "1.2.0"},
+			want: Caps{HasBasicStatus: true},
+		},
+		"LS120": {
+			info: DeviceInfoResponse{Model: "LS120", Firmware: "1.4.3"},
+			want: Caps{HasBasicStatus: true, HasS0Meter: true},
+		},
+		"LS120 enologic": {
+			info: DeviceInfoResponse{Model: "LS120", Firmware: "1.4.3-EL"},
+			want: Caps{
+				HasBasicStatus:  true,
+				HasMeterReading: true,
+				HasP1Telegram:   true,
+				HasGasMeter:     true,
+				HasWaterMeter:   true,
+				HasS0Meter:      true,
+			},
+		},
+		"LS120 enologic with phases": {
+			info: DeviceInfoResponse{Model: "LS120", Firmware: "1.5.1-EL"},
+			want: Caps{
+				HasBasicStatus:  true,
+				HasMeterReading: true,
+				HasPhaseReading: true,
+				HasP1Telegram:   true,
+				HasGasMeter:     true,
+				HasWaterMeter:   true,
+				HasS0Meter:      true,
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			have, err := capsOf(tc.info)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestClient_Request_notFound(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	_, err = c.GetPhaseReading(context.Background())
+	assert.ErrorIs(t, err, ErrUnsupportedByFirmware)
+
+	var ure *UnexpectedResponseError
+	assert.ErrorAs(t, err, &ure)
+	assert.Equal(t, http.StatusNotFound, ure.StatusCode)
+}
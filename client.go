@@ -5,32 +5,63 @@
 package youless
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	urlpkg "net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-pogo/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/singleflight"
+	"golang.org/x/time/rate"
 )
 
+// Version is the version of this package.
+const Version = "0.1.0"
+
+// DefaultAuthCookieName is the name of the auth cookie set by the device,
+// unless changed with WithAuthCookieName.
+const DefaultAuthCookieName = "tk"
+
+// DefaultUserAgent is the User-Agent header sent with all requests, unless
+// changed with WithUserAgent.
+const DefaultUserAgent = "youless-client/" + Version
+
+// DefaultMaxResponseSize is the maximum size in bytes of a response body read
+// by Request, unless changed with WithMaxResponseSize.
+const DefaultMaxResponseSize = 8 << 20
+
 //goland:noinspection GoUnusedConst
 const (
 	AttrDeviceMAC      = "youless.device.mac"
 	AttrDeviceModel    = "youless.device.model"
 	AttrDeviceFirmware = "youless.device.firmware"
+	AttrRequestID      = "youless.request.id"
 
 	ErrReadPasswordFile errors.Msg = "failed to read password file"
 	ErrPasswordRequired errors.Msg = "password required"
 	ErrInvalidPassword  errors.Msg = "invalid password"
+	ErrClientClosed     errors.Msg = "client is closed"
+	ErrNoAuthCookie     errors.Msg = "no auth cookie received"
+	ErrAuthLockout      errors.Msg = "authentication is locked out"
+	ErrResponseTooLarge errors.Msg = "response body is too large"
+
+	ErrInvalidMaxResponseSize errors.Msg = "max response size must be positive"
+
+	ErrUnexpectedContentType errors.Msg = "unexpected content type, expected json"
 )
 
 type UnexpectedResponseError struct {
@@ -41,6 +72,39 @@ func (e *UnexpectedResponseError) Error() string {
 	return fmt.Sprintf("unexpected response status code: %d, %s", e.StatusCode, http.StatusText(e.StatusCode))
 }
 
+// Is reports whether target is a sentinel error matching the status code, see
+// HTTPStatusError.
+func (e *UnexpectedResponseError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+// unexpectedContentLen is the maximum length of the body included in an
+// UnexpectedContentError.
+const unexpectedContentLen = 64
+
+// UnexpectedContentError contains the first part of a response body which
+// could not be unmarshalled because it is not json.
+type UnexpectedContentError struct {
+	Body string
+}
+
+func (e *UnexpectedContentError) Error() string {
+	return fmt.Sprintf("unexpected content: %q", e.Body)
+}
+
+func isHTML(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) != 0 && b[0] == '<'
+}
+
+func truncate(b []byte, n int) string {
+	b = bytes.TrimSpace(b)
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+	return string(b)
+}
+
 var _ APIRequester = (*Client)(nil)
 
 // Client is an APIRequester which connects with the Youless device and is able
@@ -55,16 +119,106 @@ type Client struct {
 	Config Config
 
 	log Logger
+	// logCtxKey is the key of the context value added to the messages of the
+	// default Logger
+	logCtxKey any
 	// tracer used to created trace spans
 	tracer trace.Tracer
+	// metrics used to record request metrics
+	metrics *clientMetrics
+	// stats contains the counters returned by Stats
+	stats stats
+	// limiter limits the rate of requests to the device, there is no limit
+	// when nil
+	limiter *rate.Limiter
+	// beforeHooks and afterHooks are called before and after each request
+	beforeHooks []func(req *http.Request)
+	afterHooks  []func(res *http.Response, err error)
+	// device contains the DeviceInfoResponse used to add device attributes to
+	// spans and metrics, it is fetched when needed
+	device atomic.Pointer[DeviceInfoResponse]
+	// deviceMut guards fetching device, which is retried after deviceRetry
+	// when it failed
+	deviceMut     sync.Mutex
+	deviceRetry   time.Time
+	deviceBackoff time.Duration
+	// infoCache caches the response of GetDeviceInfo when its ttl is set
+	infoCache deviceInfoCache
 	// client used to send and receive http requests
 	client http.Client
+	// clientFunc lazily creates client on first use, when set with
+	// WithHTTPClientFunc
+	clientFunc func() http.Client
+	clientOnce sync.Once
+	// created indicates NewClient has completed
+	created bool
+	// transport is the unwrapped http.RoundTripper of client, as set with
+	// WithHTTPClient or WithTransport
+	transport http.RoundTripper
+	// wrapTransport wraps the http.RoundTripper set with WithTransport, it is
+	// set by WithTracerProvider and WithDebugDump
+	wrapTransport func(rt http.RoundTripper) http.RoundTripper
 	// group makes sure multiple requests to the same url are only executed once
 	group singleflight.Group
+	// calls contains the shared contexts of the grouped requests which are
+	// in-flight
+	callsMut sync.Mutex
+	calls    map[string]*groupCall
+	// groupWindow is the duration the result of a grouped request is reused
+	// after it has completed
+	groupWindow time.Duration
+	recentMut   sync.Mutex
+	recent      map[string]recentResult
 	// cookie contains the http.Cookie received after authenticating
 	cookie atomic.Pointer[http.Cookie]
+	// store is used to load and save the auth cookie across process restarts
+	store CookieStore
+	// basicAuth contains the credentials used for HTTP Basic authentication,
+	// the auth cookie flow is skipped when set
+	basicAuth *basicAuth
+	// authCookieNames contains the accepted names of the auth cookie,
+	// DefaultAuthCookieName is used when empty
+	authCookieNames []string
+	// userAgent is the value of the User-Agent header of all requests,
+	// DefaultUserAgent is used when empty
+	userAgent string
+	// loc is the location of the device's local time
+	loc *time.Location
+	// stopOnAuth indicates redirects are not followed after the auth cookie
+	// is captured from a response
+	stopOnAuth bool
+	// manualAuth indicates the auth cookie is only captured from redirects of
+	// requests made by Authorize, and AuthCookie never calls Authorize
+	manualAuth bool
+	// telegramFallback indicates GetMeterReading derives the reading from
+	// the P1 telegram when the device does not support the meter reading page
+	telegramFallback bool
+	// basicStatusFallback indicates GetMeterReading derives the reading from
+	// the basic status when the device does not support the meter reading
+	// page
+	basicStatusFallback bool
+	// telegramMaxPages is the maximum number of pages GetP1Telegram requests,
+	// DefaultTelegramMaxPages is used when 0
+	telegramMaxPages int
+	// batchConcurrency is the maximum number of requests GetLogsBatch
+	// executes concurrently, DefaultBatchConcurrency is used when 0
+	batchConcurrency int
+	// maxResponseSize is the maximum size of a response body read by
+	// Request, DefaultMaxResponseSize is used when 0
+	maxResponseSize int64
+	// requestIDGen generates a request id for requests without one, no id is
+	// generated when nil
+	requestIDGen func() string
+	// decimals is the number of decimals float64 fields of responses are
+	// rounded to, when round is true
+	decimals int
+	round    bool
+	// closed indicates Close is called
+	closed atomic.Bool
 }
 
+type basicAuth struct{ username, password string }
+
 // NewClient creates a new Client with Config and applies any provided
 // Option(s).
 func NewClient(conf Config, opts ...Option) (*Client, error) {
@@ -75,6 +229,10 @@ func NewClient(conf Config, opts ...Option) (*Client, error) {
 	if err := c.With(opts...); err != nil {
 		return nil, err
 	}
+	if c.log == nil {
+		c.log = NopLogger()
+	}
+	c.created = true
 	return &c, nil
 }
 
@@ -90,19 +248,147 @@ func (c *Client) With(opts ...Option) error {
 	if err != nil {
 		return errors.Wrap(err, ErrApplyOption)
 	}
+	if l, ok := c.log.(*defaultLogger); ok && c.logCtxKey != nil && l.ctxKey != c.logCtxKey {
+		// the logger may be shared with other clients, which should not be
+		// affected by the options of this client
+		cp := *l
+		cp.ctxKey = c.logCtxKey
+		c.log = &cp
+	}
 	return nil
 }
 
+// Close closes any idle connections of the underlying http.Client, unless it
+// is created using WithHTTPClientFunc, and clears the auth cookie. Any calls
+// made after Close return an ErrClientClosed error. The CookieStore, if any,
+// is left untouched.
+func (c *Client) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+
+	if c.clientFunc == nil {
+		// the transport of an http.Client created by clientFunc may be
+		// shared with other Clients
+		c.client.CloseIdleConnections()
+	}
+	c.cookie.Store(nil)
+	c.infoCache.reset()
+
+	c.recentMut.Lock()
+	c.recent = nil
+	c.recentMut.Unlock()
+	return nil
+}
+
+// Location returns the location of the device's local time, which is set
+// using WithLocation. It defaults to time.Local.
+func (c *Client) Location() *time.Location { return locationOrLocal(c.loc) }
+
+// TelegramFallback indicates if the Client is created with
+// WithTelegramFallback.
+func (c *Client) TelegramFallback() bool { return c.telegramFallback }
+
+// BasicStatusFallback indicates if the Client is created with
+// WithBasicStatusFallback.
+func (c *Client) BasicStatusFallback() bool { return c.basicStatusFallback }
+
+// TelegramMaxPages returns the maximum number of pages GetP1Telegram
+// requests, which is set using WithTelegramMaxPages.
+func (c *Client) TelegramMaxPages() int {
+	if c.telegramMaxPages <= 0 {
+		return DefaultTelegramMaxPages
+	}
+	return c.telegramMaxPages
+}
+
+// BatchConcurrency returns the maximum number of requests GetLogsBatch
+// executes concurrently, which is set using WithBatchConcurrency.
+func (c *Client) BatchConcurrency() int {
+	if c.batchConcurrency <= 0 {
+		return DefaultBatchConcurrency
+	}
+	return c.batchConcurrency
+}
+
+// httpClient returns the underlying http.Client. When it is set using
+// WithHTTPClientFunc, it is created on the first call, with the handling of
+// the auth cookie and any transport wrappers applied to it.
+func (c *Client) httpClient() *http.Client {
+	c.clientOnce.Do(func() {
+		if c.clientFunc == nil {
+			return
+		}
+		c.client = c.clientFunc()
+		c.client.CheckRedirect = c.fetchAuthCookie(c.client.CheckRedirect)
+		c.transport = c.client.Transport
+		if c.wrapTransport != nil {
+			c.client.Transport = c.wrapTransport(c.client.Transport)
+		}
+	})
+	return &c.client
+}
+
+// addTransportWrapper wraps the current http.RoundTripper of the underlying
+// http.Client with wrap, and makes sure any http.RoundTripper set afterwards
+// using WithTransport is wrapped with it as well.
+func (c *Client) addTransportWrapper(wrap func(rt http.RoundTripper) http.RoundTripper) {
+	if prev := c.wrapTransport; prev != nil {
+		c.wrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			return wrap(prev(rt))
+		}
+	} else {
+		c.wrapTransport = wrap
+	}
+	c.client.Transport = wrap(c.client.Transport)
+}
+
+// MaxResponseSize returns the maximum size in bytes of a response body read
+// by Request, which is set using WithMaxResponseSize.
+func (c *Client) MaxResponseSize() int64 {
+	if c.maxResponseSize <= 0 {
+		return DefaultMaxResponseSize
+	}
+	return c.maxResponseSize
+}
+
 // AuthCookie returns the http.Cookie used for authentication. If the cookie is
 // not yet fetched, it will try to fetch it by calling Authorize with the
 // contents of Config.PasswordFile or Config.Password as password. When both
 // fields are empty, it will return a nil http.Cookie, indicating the YouLess
-// device does not need an auth cookie to access it's api.
+// device does not need an auth cookie to access it's api. Use AuthRequired to
+// check if the device actually requires authentication.
+// When a CookieStore is set using WithCookieStore, a stored cookie which is not
+// yet expired is used before trying to authorize with the device.
+// AuthCookie always returns a nil http.Cookie when HTTP Basic authentication is
+// set using WithBasicAuth. When WithManualAuth is used, it never calls
+// Authorize and returns a nil http.Cookie when there is no cookie yet.
 func (c *Client) AuthCookie(ctx context.Context) (*http.Cookie, error) {
+	if c.closed.Load() {
+		return nil, errors.New(ErrClientClosed)
+	}
+	if c.basicAuth != nil {
+		return nil, nil
+	}
 	if cookie := c.cookie.Load(); cookie != nil {
 		return cookie, nil
 	}
 
+	if c.store != nil {
+		cookie, err := c.store.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, ErrLoadAuthCookie)
+		}
+		if cookieValid(cookie) {
+			c.cookie.Store(cookie)
+			return cookie, nil
+		}
+	}
+
+	if c.manualAuth {
+		return nil, nil
+	}
+
 	if c.Config.PasswordFile != "" {
 		pw, err := os.ReadFile(c.Config.PasswordFile)
 		if err != nil {
@@ -127,11 +413,97 @@ func (c *Client) AuthCookie(ctx context.Context) (*http.Cookie, error) {
 	return nil, nil
 }
 
+// InvalidateAuth clears the auth cookie, so it is fetched again with the next
+// request. When a CookieStore is set using WithCookieStore, the stored cookie
+// is cleared as well.
+// Request calls InvalidateAuth itself when the device responds to a request
+// with a previously accepted auth cookie as if it is unauthenticated, after
+// which the request is retried once. This does not apply when WithManualAuth
+// is used.
+func (c *Client) InvalidateAuth() error {
+	return c.invalidateAuth(c.cookie.Load())
+}
+
+// invalidateAuth clears the auth cookie when it still has the value of cookie.
+// This prevents clearing a newer cookie which is fetched in the meantime.
+func (c *Client) invalidateAuth(cookie *http.Cookie) error {
+	current := c.cookie.Load()
+	if cookie == nil || current == nil || current.Value != cookie.Value ||
+		!c.cookie.CompareAndSwap(current, nil) {
+		return nil
+	}
+
+	// results received with the invalid cookie must not be reused
+	c.recentMut.Lock()
+	c.recent = nil
+	c.recentMut.Unlock()
+
+	if c.store != nil {
+		// an empty cookie is never valid, see cookieValid
+		if err := c.store.Save(&http.Cookie{Name: cookie.Name}); err != nil {
+			return errors.Wrap(err, ErrSaveAuthCookie)
+		}
+	}
+	return nil
+}
+
+// Ping checks whether the device is reachable and the Client is authenticated,
+// e.g. for use in a readiness probe. It requests the device info page,
+// authorizing first when needed, without decoding its response. It returns an
+// ErrPasswordRequired error when the device requires authentication but no
+// (valid) password is set, or the error of the failed request otherwise.
+func (c *Client) Ping(ctx context.Context) error {
+	var raw []byte
+	return c.Request(withFuncName(ctx, "Ping"), "d", &raw)
+}
+
+// AuthRequired sends a GET request, without auth cookie, to the YouLess device
+// and reports whether the device requires authentication to access its api.
+func (c *Client) AuthRequired(ctx context.Context) (bool, error) {
+	if c.closed.Load() {
+		return false, errors.New(ErrClientClosed)
+	}
+	if c.log == nil {
+		c.log = NopLogger()
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	url := c.Config.url("d")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	req.Header.Set("User-Agent", c.userAgentOrDefault())
+
+	c.log.LogClientRequest(ctx, c.Config.Name, url, false)
+
+	res, err := c.do(req)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusUnauthorized {
+		return true, nil
+	}
+	if res.StatusCode > 400 {
+		return false, errors.WithStack(&UnexpectedResponseError{
+			StatusCode: res.StatusCode,
+		})
+	}
+	return false, nil
+}
+
 // Authorize sends a POST groupRequest to the YouLess device with the provided
 // password. If the password is correct, it will return the received auth cookie
 // from the device's api. Otherwise, it will return an ErrInvalidPassword error.
 // Calling Authorize will replace any existing auth cookie with the new one.
 func (c *Client) Authorize(ctx context.Context, password string) (_ http.Cookie, err error) {
+	if c.closed.Load() {
+		return http.Cookie{}, errors.New(ErrClientClosed)
+	}
 	if c.log == nil {
 		c.log = NopLogger()
 	}
@@ -141,9 +513,12 @@ func (c *Client) Authorize(ctx context.Context, password string) (_ http.Cookie,
 		defer span.End()
 	}
 
-	_, err = c.groupRequest(ctx, "auth", c.Config.BaseURL, func() (any, error) {
+	ctx, cancel := c.withDefaultDeadline(ctx)
+	defer cancel()
+
+	_, err = c.groupRequest(ctx, authGroup, c.Config.BaseURL, func(ctx context.Context) (_ any, err error) {
 		req, err := http.NewRequestWithContext(
-			ctx,
+			context.WithValue(ctx, authRequest{}, true),
 			http.MethodPost,
 			c.Config.BaseURL,
 			strings.NewReader(urlpkg.Values{"w": {password}}.Encode()),
@@ -151,17 +526,21 @@ func (c *Client) Authorize(ctx context.Context, password string) (_ http.Cookie,
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		c.setHeaders(req)
 
-		c.log.LogClientRequest(ctx, c.Config.Name, c.Config.BaseURL, false)
-		c.client.Timeout = c.Config.Timeout
-
-		res, err := c.client.Do(req)
+		res, err := c.do(req)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
+		defer errors.AppendFunc(&err, res.Body.Close)
 
-		if res.StatusCode == 403 {
-			return nil, errors.New(ErrInvalidPassword)
+		if lockout := authLockout(res); lockout != nil {
+			return nil, errors.Wrap(lockout, ErrAuthLockout)
+		}
+		if res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusUnauthorized {
+			return nil, errors.Wrap(&HTTPStatusError{
+				StatusCode: res.StatusCode,
+			}, ErrInvalidPassword)
 		}
 		if res.StatusCode > 400 {
 			return nil, errors.WithStack(&UnexpectedResponseError{
@@ -178,20 +557,161 @@ func (c *Client) Authorize(ctx context.Context, password string) (_ http.Cookie,
 		return http.Cookie{}, err
 	}
 
-	return *c.cookie.Load(), nil
+	cookie := c.cookie.Load()
+	if cookie == nil {
+		// the device did not redirect with a cookie with an accepted name
+		return http.Cookie{}, errors.New(ErrNoAuthCookie)
+	}
+	return *cookie, nil
+}
+
+// AuthLockoutError indicates the device temporarily refuses authentication,
+// after too many attempts with an invalid password.
+type AuthLockoutError struct {
+	// RetryAfter is the duration after which authentication is allowed again,
+	// it is zero when unknown.
+	RetryAfter time.Duration
+}
+
+func (e *AuthLockoutError) Error() string {
+	if e.RetryAfter <= 0 {
+		return "too many failed attempts"
+	}
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter)
+}
+
+// authLockoutBodyLen is the maximum amount of bytes of the response body which
+// are read to detect a lockout.
+const authLockoutBodyLen = 512
+
+// authLockout returns an AuthLockoutError when res indicates the device locked
+// out authentication. This is the case when the status code is 429, or 403
+// with a Retry-After header or a body mentioning the lockout.
+func authLockout(res *http.Response) *AuthLockoutError {
+	var retryAfter time.Duration
+	if v := res.Header.Get("Retry-After"); v != "" {
+		if sec, err := strconv.Atoi(v); err == nil && sec > 0 {
+			retryAfter = time.Duration(sec) * time.Second
+		}
+	}
+
+	switch res.StatusCode {
+	case http.StatusTooManyRequests:
+		return &AuthLockoutError{RetryAfter: retryAfter}
+
+	case http.StatusForbidden:
+		if res.Header.Get("Retry-After") != "" {
+			return &AuthLockoutError{RetryAfter: retryAfter}
+		}
+		b, _ := io.ReadAll(io.LimitReader(res.Body, authLockoutBodyLen))
+		if bytes.Contains(bytes.ToLower(b), []byte("locked")) {
+			return &AuthLockoutError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// setHeaders sets the User-Agent header and, when set, the HTTP Basic
+// authentication credentials on req.
+func (c *Client) setHeaders(req *http.Request) {
+	req.Header.Set("User-Agent", c.userAgentOrDefault())
+	if c.basicAuth != nil {
+		req.SetBasicAuth(c.basicAuth.username, c.basicAuth.password)
+	}
+}
+
+func (c *Client) userAgentOrDefault() string {
+	if c.userAgent != "" {
+		return c.userAgent
+	}
+	return DefaultUserAgent
+}
+
+// withTimeout returns a copy of ctx which is canceled after Config.Timeout, or
+// the timeout set with WithCallTimeout. DefaultTimeout is used when neither
+// is set. When ctx already has an earlier deadline, that deadline is kept.
+// Within a call which budgets its timeout across multiple requests, all
+// requests share the same deadline.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout := c.timeout(ctx)
+	if b, ok := ctx.Value(timeoutBudget{}).(*budget); ok {
+		return context.WithDeadline(ctx, b.deadline(timeout))
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// withDefaultDeadline returns a copy of ctx which is canceled after
+// Config.Timeout, or the timeout set with WithCallTimeout, when ctx has no
+// deadline. DefaultTimeout is used when neither is set. This makes sure a
+// call, including any authorization and retry, cannot outlive the timeout,
+// even when the underlying http.Client has no timeout. Within a call which
+// budgets its timeout, the deadline of the budget is used. A ctx with a
+// deadline is returned as is.
+func (c *Client) withDefaultDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	if b, ok := ctx.Value(timeoutBudget{}).(*budget); ok {
+		return context.WithDeadline(ctx, b.deadline(c.timeout(ctx)))
+	}
+
+	return context.WithTimeout(ctx, c.timeout(ctx))
+}
+
+// timeout returns the timeout set with WithCallTimeout, or Config.Timeout.
+// DefaultTimeout is used when neither is positive.
+func (c *Client) timeout(ctx context.Context) time.Duration {
+	timeout := c.Config.Timeout
+	if d, ok := ctx.Value(callTimeout{}).(time.Duration); ok {
+		timeout = d
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return timeout
 }
 
 type checkRedirectFunc func(req *http.Request, via []*http.Request) error
 
+// authRequest is the context key which marks requests made by Authorize.
+type authRequest struct{}
+
 func (c *Client) fetchAuthCookie(next checkRedirectFunc) checkRedirectFunc {
 	return func(req *http.Request, via []*http.Request) error {
-		if req.Response != nil {
-			for _, cookie := range req.Response.Cookies() {
-				if cookie.Name == "tk" {
-					c.log.LogFetchAuthCookie(c.Config.Name, *cookie)
-					c.cookie.Store(cookie)
+		if req.Response != nil && (!c.manualAuth || req.Context().Value(authRequest{}) != nil) {
+			cookie := c.authCookieOf(req.Response)
+			if cookie != nil {
+				cookieExpires(cookie)
+				c.log.LogFetchAuthCookie(c.Config.Name, *cookie)
+				c.cookie.Store(cookie)
+
+				if c.store != nil {
+					if err := c.store.Save(cookie); err != nil {
+						return errors.Wrap(err, ErrSaveAuthCookie)
+					}
+				}
+				if c.stopOnAuth || req.Context().Value(authRequest{}) != nil {
+					// Authorize only needs the cookie, not the page it
+					// redirects to
 					return http.ErrUseLastResponse
 				}
+				// follow the redirect to the actual content, which may
+				// require the cookie
+				if c.isDeviceHost(req.URL) {
+					req.AddCookie(cookie)
+				}
+			} else {
+				if l, ok := c.log.(unknownAuthCookiesLogger); ok {
+					if cookies := req.Response.Cookies(); len(cookies) != 0 {
+						l.LogUnknownAuthCookies(c.Config.Name, cookies)
+					}
+				}
+				// keep sending a cookie captured from an earlier hop
+				if cookie = c.cookie.Load(); cookie != nil && c.isDeviceHost(req.URL) {
+					if _, err := req.Cookie(cookie.Name); err != nil {
+						req.AddCookie(cookie)
+					}
+				}
 			}
 		}
 		if next != nil {
@@ -201,7 +721,40 @@ func (c *Client) fetchAuthCookie(next checkRedirectFunc) checkRedirectFunc {
 	}
 }
 
+// isDeviceHost reports whether u points to the host of the device's BaseURL.
+// The auth cookie must never be sent to other hosts a redirect points to.
+func (c *Client) isDeviceHost(u *urlpkg.URL) bool {
+	base, err := urlpkg.Parse(c.Config.BaseURL)
+	return err == nil && strings.EqualFold(base.Host, u.Host)
+}
+
+// authCookieOf returns the first cookie of res with an accepted auth cookie
+// name, or nil when there is none.
+func (c *Client) authCookieOf(res *http.Response) *http.Cookie {
+	for _, cookie := range res.Cookies() {
+		if c.isAuthCookie(cookie.Name) {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func (c *Client) isAuthCookie(name string) bool {
+	if len(c.authCookieNames) == 0 {
+		return name == DefaultAuthCookieName
+	}
+	for _, n := range c.authCookieNames {
+		if name == n {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *Client) Request(ctx context.Context, page string, out any) (err error) {
+	if c.closed.Load() {
+		return errors.New(ErrClientClosed)
+	}
 	if c.log == nil {
 		c.log = NopLogger()
 	}
@@ -211,12 +764,15 @@ func (c *Client) Request(ctx context.Context, page string, out any) (err error)
 		defer span.End()
 	}
 
+	ctx, cancel := c.withDefaultDeadline(ctx)
+	defer cancel()
+
 	url := c.Config.url(page)
-	b, err := c.groupRequest(ctx, page, url, func() (_ any, err error) {
-		cookie, err := c.AuthCookie(ctx)
-		if err != nil {
-			return nil, err
-		}
+	// authed is the auth cookie the request is sent with, it is nil when the
+	// request is grouped with another request or sent without cookie
+	var authed, cookie *http.Cookie
+	fetch := func(ctx context.Context) (_ any, err error) {
+		authed = cookie
 
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 		if err != nil {
@@ -225,17 +781,23 @@ func (c *Client) Request(ctx context.Context, page string, out any) (err error)
 		if cookie != nil {
 			req.AddCookie(cookie)
 		}
+		c.setHeaders(req)
 
-		c.log.LogClientRequest(ctx, c.Config.Name, url, false)
-		c.client.Timeout = c.Config.Timeout
-
-		res, err := c.client.Do(req)
+		res, err := c.do(req)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
 
-		if res.StatusCode == http.StatusForbidden {
-			return nil, errors.New(ErrPasswordRequired)
+		if res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusUnauthorized {
+			return nil, errors.Wrap(&HTTPStatusError{
+				StatusCode: res.StatusCode,
+			}, ErrPasswordRequired)
+		}
+		if res.StatusCode == http.StatusNotFound {
+			// the device's firmware does not support the requested page
+			return nil, errors.Wrap(&UnexpectedResponseError{
+				StatusCode: res.StatusCode,
+			}, ErrUnsupportedByFirmware)
 		}
 		if res.StatusCode > 400 {
 			return nil, errors.WithStack(&UnexpectedResponseError{
@@ -244,13 +806,32 @@ func (c *Client) Request(ctx context.Context, page string, out any) (err error)
 		}
 
 		defer errors.AppendFunc(&err, res.Body.Close)
-		b, err := io.ReadAll(res.Body)
-		if err != nil {
-			err = errors.WithStack(err)
+		return readBody(res, c.MaxResponseSize())
+	}
+
+	// the auth cookie is fetched using the caller's context, so the shared
+	// request does not include the authorization of the caller which happens
+	// to be first
+	send := func() (any, error) {
+		var err error
+		if cookie, err = c.AuthCookie(ctx); err != nil {
 			return nil, err
 		}
-		return b, nil
-	})
+		return c.groupRequest(ctx, page, url, fetch)
+	}
+
+	b, err := send()
+	// authed is only set by the executed fetch, which has completed when its
+	// error is returned
+	if err != nil && errors.Is(err, ErrPasswordRequired) && authed != nil && !c.manualAuth {
+		// the auth cookie is most likely expired, clear it so AuthCookie
+		// authorizes again and retry the request once
+		if err = c.invalidateAuth(authed); err != nil {
+			return err
+		}
+		authed = nil
+		b, err = send()
+	}
 	if err != nil {
 		return err
 	}
@@ -261,14 +842,222 @@ func (c *Client) Request(ctx context.Context, page string, out any) (err error)
 		return nil
 	}
 
-	if err = json.Unmarshal(b.([]byte), &out); err != nil {
-		err = errors.WithStack(err)
+	if err = decode(b.([]byte), out); err != nil {
 		return err
 	}
+	if c.round {
+		roundResponse(out, c.decimals)
+	}
+	return nil
+}
+
+// RawResponse contains the unprocessed response of the YouLess device.
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RequestRaw sends an authenticated GET request to the page of the YouLess
+// device and returns its unprocessed response. Unlike Request, the request is
+// never grouped with other requests and a response with an unexpected status
+// code is not treated as an error. This makes it useful to diagnose quirks of
+// the device's firmware.
+func (c *Client) RequestRaw(ctx context.Context, page string) (_ *RawResponse, err error) {
+	if c.closed.Load() {
+		return nil, errors.New(ErrClientClosed)
+	}
+	if c.log == nil {
+		c.log = NopLogger()
+	}
+	if name, ok := ctx.Value(apiFuncName{}).(string); ok && c.tracer != nil {
+		var span trace.Span
+		ctx, span = c.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+		defer span.End()
+	}
+
+	cookie, err := c.AuthCookie(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	url := c.Config.url(page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	c.setHeaders(req)
+
+	c.log.LogClientRequest(ctx, c.Config.Name, url, false)
+
+	res, err := c.do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	defer errors.AppendFunc(&err, res.Body.Close)
+	b, err := readLimited(res.Body, c.MaxResponseSize())
+	if err != nil {
+		return nil, err
+	}
+
+	return &RawResponse{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       b,
+	}, nil
+}
+
+// do sends req using the underlying http.Client and calls the hooks set with
+// WithRequestHook. When a rate limit is set using WithRateLimit, it blocks
+// until the request is allowed or req's context is done.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(req.Context()); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	for _, fn := range c.beforeHooks {
+		fn(req)
+	}
+	res, err := c.httpClient().Do(req)
+	for _, fn := range c.afterHooks {
+		fn(res, err)
+	}
+	return res, err
+}
+
+// readBody reads the body of res and decompresses it when it is encoded with
+// gzip or deflate. Compressed responses which are requested by the
+// http.Transport itself are already decompressed by it. It returns an
+// ErrResponseTooLarge error when the (decompressed) body exceeds limit bytes.
+func readBody(res *http.Response, limit int64) ([]byte, error) {
+	var r io.Reader
+	switch strings.ToLower(res.Header.Get("Content-Encoding")) {
+	case "gzip":
+		gr, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer gr.Close()
+		r = gr
+
+	case "deflate":
+		zr, err := zlib.NewReader(res.Body)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer zr.Close()
+		r = zr
+
+	default:
+		r = res.Body
+	}
+
+	return readLimited(r, limit)
+}
+
+// readLimited reads all data from r. It returns an ErrResponseTooLarge error
+// when r contains more than limit bytes.
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	// read one more byte than allowed to detect a body which exceeds limit
+	b, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if int64(len(b)) > limit {
+		return nil, errors.Wrap(errors.Newf("exceeds %d bytes", limit), ErrResponseTooLarge)
+	}
+	return b, nil
+}
+
+// Command sends an authenticated POST request with form values to the page of
+// the YouLess device. It is used for api calls which change the state of the
+// device.
+func (c *Client) Command(ctx context.Context, page string, form urlpkg.Values) (err error) {
+	if c.closed.Load() {
+		return errors.New(ErrClientClosed)
+	}
+	if c.log == nil {
+		c.log = NopLogger()
+	}
+	if name, ok := ctx.Value(apiFuncName{}).(string); ok && c.tracer != nil {
+		var span trace.Span
+		ctx, span = c.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+		defer func() {
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+			span.End()
+		}()
+	}
+
+	cookie, err := c.AuthCookie(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	url := c.Config.url(page)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	c.setHeaders(req)
+
+	c.log.LogClientRequest(ctx, c.Config.Name, url, false)
+
+	res, err := c.do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusUnauthorized {
+		return errors.Wrap(&HTTPStatusError{
+			StatusCode: res.StatusCode,
+		}, ErrPasswordRequired)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return errors.Wrap(&UnexpectedResponseError{
+			StatusCode: res.StatusCode,
+		}, ErrUnsupportedByFirmware)
+	}
+	if res.StatusCode > 400 {
+		return errors.WithStack(&UnexpectedResponseError{
+			StatusCode: res.StatusCode,
+		})
+	}
 	return nil
 }
 
-func (c *Client) groupRequest(ctx context.Context, groupName, url string, fn func() (any, error)) (_ any, err error) {
+// groupRequest executes fn once for all concurrent callers requesting page.
+// fn receives a context with the values of the first caller, which stays
+// alive for as long as any of the callers' contexts is active, and is
+// cancelled when all of them are done. A caller whose context is done, or
+// whose deadline has passed, stops waiting and returns its context's error.
+func (c *Client) groupRequest(ctx context.Context, page, url string, fn func(ctx context.Context) (any, error)) (_ any, err error) {
+	id, ok := RequestID(ctx)
+	if !ok && c.requestIDGen != nil {
+		id = c.requestIDGen()
+		ctx = WithRequestID(ctx, id)
+	}
+
+	attrs := c.deviceAttrs(ctx, page)
+
 	var span trace.Span
 	if c.tracer != nil {
 		ctx, span = c.tracer.Start(ctx, "request",
@@ -277,7 +1066,11 @@ func (c *Client) groupRequest(ctx context.Context, groupName, url string, fn fun
 				semconv.RPCService(c.Config.Name),
 				semconv.ServerSocketDomain(c.Config.BaseURL),
 			),
+			trace.WithAttributes(attrs...),
 		)
+		if id != "" {
+			span.SetAttributes(attribute.String(AttrRequestID, id))
+		}
 		defer func() {
 			if err == nil {
 				span.SetStatus(codes.Ok, "")
@@ -289,11 +1082,230 @@ func (c *Client) groupRequest(ctx context.Context, groupName, url string, fn fun
 		}()
 	}
 
-	res, err, shared := c.group.Do(groupName, fn)
+	groupName := c.groupKey(page)
+	// only the results of GET requests to pages are reused, the POST request
+	// of Authorize must always be sent to get a new auth cookie
+	reuse := page != authGroup
+	if reuse {
+		if res, ok := c.recentResult(groupName); ok {
+			c.stats.record(true, nil)
+			c.log.LogClientRequest(ctx, c.Config.Name, url, true)
+			return res, nil
+		}
+	}
+
+	// the caller waits for the result until its deadline, the shared request
+	// lasts until the deadlines of all of its callers have passed
+	call := c.joinGroupCall(ctx, groupName)
+	defer c.leaveGroupCall(groupName, call)
+	if span != nil {
+		span.AddLink(trace.LinkFromContext(call.ctx))
+	}
+
+	var executed atomic.Bool
+	ch := c.group.DoChan(groupName, func() (any, error) {
+		executed.Store(true)
+		// the request is logged with the context of the caller which
+		// executes it, fn itself only receives the shared context
+		c.log.LogClientRequest(ctx, c.Config.Name, url, false)
+		start := time.Now()
+		res, err := fn(call.ctx)
+		c.stats.recordLatency(time.Since(start))
+		if c.metrics != nil {
+			c.metrics.record(call.ctx, page, time.Since(start), err,
+				append(attrs, semconv.RPCService(c.Config.Name))...,
+			)
+		}
+		if err == nil && reuse {
+			c.storeRecentResult(groupName, res)
+		}
+		return res, err
+	})
+
+	select {
+	case <-ctx.Done():
+		err = errors.WithStack(ctx.Err())
+		c.stats.record(!executed.Load(), err)
+		return nil, err
+
+	case r := <-ch:
+		// only the caller which executed fn did not share the result of another
+		c.stats.record(!executed.Load(), r.Err)
+		if r.Shared {
+			c.log.LogClientRequest(ctx, c.Config.Name, url, true)
+		}
+		return r.Val, r.Err
+	}
+}
+
+// groupCall is the shared context of an in-flight grouped request.
+type groupCall struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// span is the trace span of the request, the spans of all callers link
+	// to it
+	span trace.Span
+	// refs is the amount of callers waiting for the result of the request
+	refs int
+}
+
+// joinGroupCall returns the groupCall of the in-flight request with
+// groupName, or creates a new one. Its context keeps the values, such as the
+// trace span and request id, of the caller which happens to be first, but not
+// its cancellation or deadline. The request is cancelled once all of its
+// callers are done, see leaveGroupCall.
+func (c *Client) joinGroupCall(ctx context.Context, groupName string) *groupCall {
+	c.callsMut.Lock()
+	defer c.callsMut.Unlock()
+
+	call, ok := c.calls[groupName]
+	if !ok {
+		if c.calls == nil {
+			c.calls = make(map[string]*groupCall)
+		}
+		call = new(groupCall)
+		call.ctx, call.cancel = context.WithCancel(context.WithoutCancel(ctx))
+		if c.tracer != nil {
+			call.ctx, call.span = c.tracer.Start(call.ctx, "shared request",
+				trace.WithSpanKind(trace.SpanKindInternal),
+			)
+			if id, ok := RequestID(call.ctx); ok {
+				call.span.SetAttributes(attribute.String(AttrRequestID, id))
+			}
+		}
+		c.calls[groupName] = call
+	}
+	call.refs++
+	return call
+}
+
+// leaveGroupCall removes a caller from call. When it was the last caller, the
+// shared context is cancelled and the request is forgotten, so new callers
+// never join a request which is being cancelled.
+func (c *Client) leaveGroupCall(groupName string, call *groupCall) {
+	c.callsMut.Lock()
+	defer c.callsMut.Unlock()
+
+	call.refs--
+	if call.refs > 0 {
+		return
+	}
+	call.cancel()
+	if call.span != nil {
+		call.span.End()
+	}
+	if c.calls[groupName] == call {
+		delete(c.calls, groupName)
+	}
 	c.group.Forget(groupName)
-	if shared {
-		c.log.LogClientRequest(ctx, c.Config.Name, url, true)
+}
+
+const (
+	deviceRetryMin = time.Second
+	deviceRetryMax = 5 * time.Minute
+)
+
+// deviceAttrs returns the device attributes which are added to spans and
+// metrics. The DeviceInfoResponse is fetched once, unless it is already set
+// using WithDeviceInfo. A failure to fetch it is not fatal, the attributes are
+// skipped instead and fetching is retried with an exponential backoff.
+func (c *Client) deviceAttrs(ctx context.Context, page string) []attribute.KeyValue {
+	if c.tracer == nil && c.metrics == nil {
+		return nil
 	}
 
-	return res, err
+	device := c.device.Load()
+	// prevent fetching the device info from within the requests which are
+	// needed to fetch it
+	if device == nil && page != "d" && page != authGroup {
+		device = c.fetchDevice(ctx)
+	}
+	if device == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		attribute.String(AttrDeviceMAC, device.MAC),
+		attribute.String(AttrDeviceModel, device.Model),
+		attribute.String(AttrDeviceFirmware, device.Firmware),
+	}
+}
+
+// fetchDevice requests the DeviceInfoResponse and stores it in device. It
+// returns nil when the request fails, or when the backoff of a previous
+// failure has not yet expired.
+func (c *Client) fetchDevice(ctx context.Context) *DeviceInfoResponse {
+	c.deviceMut.Lock()
+	defer c.deviceMut.Unlock()
+
+	// another caller may have fetched it while waiting for the lock
+	if device := c.device.Load(); device != nil {
+		return device
+	}
+	if time.Now().Before(c.deviceRetry) {
+		return nil
+	}
+
+	info, err := c.GetDeviceInfo(ctx)
+	if err != nil {
+		c.deviceBackoff = min(max(c.deviceBackoff*2, deviceRetryMin), deviceRetryMax)
+		c.deviceRetry = time.Now().Add(c.deviceBackoff)
+		return nil
+	}
+
+	c.deviceBackoff = 0
+	c.device.Store(&info)
+	return &info
+}
+
+// groupKey returns the key used to group requests to page. It includes the
+// auth cookie so requests made with different auth states are never grouped.
+func (c *Client) groupKey(page string) string {
+	if cookie := c.cookie.Load(); cookie != nil {
+		return page + "\x00" + cookie.Value
+	}
+	return page
+}
+
+// authGroup is the group name of the requests of Authorize.
+const authGroup = "auth"
+
+type recentResult struct {
+	res     any
+	expires time.Time
+}
+
+func (c *Client) recentResult(groupName string) (any, bool) {
+	if c.groupWindow <= 0 {
+		return nil, false
+	}
+
+	c.recentMut.Lock()
+	defer c.recentMut.Unlock()
+
+	r, ok := c.recent[groupName]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(r.expires) {
+		delete(c.recent, groupName)
+		return nil, false
+	}
+	return r.res, true
+}
+
+func (c *Client) storeRecentResult(groupName string, res any) {
+	if c.groupWindow <= 0 {
+		return
+	}
+
+	c.recentMut.Lock()
+	defer c.recentMut.Unlock()
+
+	if c.recent == nil {
+		c.recent = make(map[string]recentResult, 4)
+	}
+	c.recent[groupName] = recentResult{
+		res:     res,
+		expires: time.Now().Add(c.groupWindow),
+	}
 }
@@ -0,0 +1,957 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestClient_AuthRequired(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		"not required": {status: http.StatusOK, want: false},
+		"required":     {status: http.StatusForbidden, want: true},
+		"unexpected":   {status: http.StatusInternalServerError, wantErr: true},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/d", r.URL.Path)
+				assert.Empty(t, r.Cookies())
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			c, err := NewClient(Config{BaseURL: srv.URL})
+			assert.NoError(t, err)
+
+			have, err := c.AuthRequired(context.Background())
+			if tc.wantErr {
+				var ure *UnexpectedResponseError
+				assert.ErrorAs(t, err, &ure)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, have)
+		})
+	}
+}
+
+func TestClient_Request_timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("context deadline", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL, Timeout: time.Minute})
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = c.GetDeviceInfo(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+	t.Run("config timeout", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 10 * time.Millisecond})
+		assert.NoError(t, err)
+
+		start := time.Now()
+		_, err = c.GetDeviceInfo(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+	})
+}
+
+func TestClient_basicAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "pass" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Empty(t, r.Cookies())
+		_, _ = w.Write([]byte(`{"model":"LS120"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("valid", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "ignored"},
+			WithBasicAuth("user", "pass"),
+		)
+		assert.NoError(t, err)
+
+		have, err := c.GetDeviceInfo(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "LS120", have.Model)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithBasicAuth("user", "wrong"))
+		assert.NoError(t, err)
+
+		_, err = c.GetDeviceInfo(context.Background())
+		assert.ErrorIs(t, err, ErrPasswordRequired)
+	})
+}
+
+func TestClient_groupRequest(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL}, WithGroupWindow(time.Second))
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			have, err := c.GetMeterReading(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, int64(350), have.Power)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), hits.Load())
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(20), stats.Requests)
+	assert.Equal(t, uint64(19), stats.Shared)
+	assert.Equal(t, uint64(0), stats.Errors)
+	assert.GreaterOrEqual(t, stats.AverageLatency, 20*time.Millisecond)
+}
+
+func TestClient_groupRequest_cancel(t *testing.T) {
+	started := make(chan struct{}, 1)
+	stopped := make(chan struct{})
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-r.Context().Done():
+			close(stopped)
+		case <-release:
+			_, _ = w.Write([]byte(`[{"pwr":350}]`))
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	t.Run("all participants cancel", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		ctx2, cancel2 := context.WithCancel(context.Background())
+
+		errs := make(chan error, 2)
+		go func() {
+			_, err := c.GetMeterReading(ctx1)
+			errs <- err
+		}()
+		<-started
+		go func() {
+			_, err := c.GetMeterReading(ctx2)
+			errs <- err
+		}()
+		// wait until the second caller joined the in-flight request
+		assert.Eventually(t, func() bool { return groupCallRefs(c) == 2 }, time.Second, time.Millisecond)
+
+		cancel1()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		select {
+		case <-stopped:
+			t.Fatal("fetch stopped while a participant is still waiting")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		cancel2()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		select {
+		case <-stopped:
+		case <-time.After(time.Second):
+			t.Fatal("fetch did not stop after all participants cancelled")
+		}
+		assert.Equal(t, 0, groupCallRefs(c))
+	})
+	t.Run("remaining participant receives result", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := c.GetMeterReading(ctx1)
+			errs <- err
+		}()
+		<-started
+
+		res := make(chan MeterReadingResponse, 1)
+		go func() {
+			have, err := c.GetMeterReading(context.Background())
+			assert.NoError(t, err)
+			res <- have
+		}()
+		assert.Eventually(t, func() bool { return groupCallRefs(c) == 2 }, time.Second, time.Millisecond)
+
+		cancel1()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+
+		release <- struct{}{}
+		assert.Equal(t, int64(350), (<-res).Power)
+	})
+}
+
+func TestClient_groupRequest_sharedContext(t *testing.T) {
+	started := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		time.Sleep(60 * time.Millisecond)
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	var sentID string
+	tracer := &attrTracer{Tracer: noop.NewTracerProvider().Tracer("")}
+	c, err := NewClient(Config{BaseURL: srv.URL},
+		WithTracer(tracer),
+		WithDeviceInfo(DeviceInfoResponse{}),
+		WithRequestIDGenerator(func() string { return "second" }),
+		WithTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			sentID, _ = RequestID(req.Context())
+			return http.DefaultTransport.RoundTrip(req)
+		})),
+	)
+	assert.NoError(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		ctx := WithRequestID(context.Background(), "first")
+		_, err := c.GetMeterReading(WithCallTimeout(ctx, 20*time.Millisecond))
+		errs <- err
+	}()
+	<-started
+
+	have, err := c.GetMeterReading(WithCallTimeout(context.Background(), time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(350), have.Power)
+	assert.ErrorIs(t, <-errs, context.DeadlineExceeded)
+
+	assert.Contains(t, tracer.attrs, attribute.String(AttrRequestID, "first"))
+	assert.Contains(t, tracer.attrs, attribute.String(AttrRequestID, "second"))
+	// the shared request keeps the values of the first caller, but outlives
+	// its timeout
+	assert.Equal(t, "first", sentID)
+}
+
+// groupCallRefs returns the total amount of callers waiting for the results
+// of in-flight grouped requests.
+func groupCallRefs(c *Client) (n int) {
+	c.callsMut.Lock()
+	defer c.callsMut.Unlock()
+	for _, call := range c.calls {
+		n += call.refs
+	}
+	return n
+}
+
+func TestClient_Close(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+	c.cookie.Store(&http.Cookie{Name: "tk", Value: "secret"})
+
+	_, err = c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Close())
+	assert.Nil(t, c.cookie.Load())
+	assert.NoError(t, c.Close(), "closing twice")
+
+	_, err = c.GetMeterReading(context.Background())
+	assert.ErrorIs(t, err, ErrClientClosed)
+	_, err = c.AuthRequired(context.Background())
+	assert.ErrorIs(t, err, ErrClientClosed)
+	_, err = c.Authorize(context.Background(), "secret")
+	assert.ErrorIs(t, err, ErrClientClosed)
+	assert.ErrorIs(t, c.Command(context.Background(), "a", nil), ErrClientClosed)
+}
+
+func TestClient_RequestRaw(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/f" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		have, err := c.RequestRaw(context.Background(), "e")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, have.StatusCode)
+		assert.Equal(t, "application/json", have.Header.Get("Content-Type"))
+		assert.Equal(t, []byte(`[{"pwr":350}]`), have.Body)
+	})
+	t.Run("unexpected status", func(t *testing.T) {
+		have, err := c.RequestRaw(context.Background(), "f")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, have.StatusCode)
+	})
+	t.Run("too large", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithMaxResponseSize(4))
+		assert.NoError(t, err)
+
+		_, err = c.RequestRaw(context.Background(), "e")
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+	t.Run("nil logger", func(t *testing.T) {
+		c := &Client{Config: Config{BaseURL: srv.URL}}
+		have, err := c.RequestRaw(context.Background(), "e")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(`[{"pwr":350}]`), have.Body)
+	})
+}
+
+func TestWithUserAgent(t *testing.T) {
+	var have atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		have.Store(r.UserAgent())
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	t.Run("default", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultUserAgent, have.Load())
+	})
+	t.Run("custom", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithUserAgent("my-app/1.0"))
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "my-app/1.0", have.Load())
+	})
+}
+
+type unknownCookiesLogger struct {
+	Logger
+	names []string
+}
+
+func (l *unknownCookiesLogger) LogUnknownAuthCookies(_ string, cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		l.names = append(l.names, cookie.Name)
+	}
+}
+
+func TestWithAuthCookieName(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("default", func(t *testing.T) {
+		log := &unknownCookiesLogger{Logger: NopLogger()}
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithLogger(log))
+		assert.NoError(t, err)
+
+		_, err = c.Authorize(context.Background(), "password")
+		assert.ErrorIs(t, err, ErrNoAuthCookie)
+		assert.Equal(t, []string{"session"}, log.names)
+	})
+	t.Run("custom", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithAuthCookieName("tk", "session"))
+		assert.NoError(t, err)
+
+		have, err := c.Authorize(context.Background(), "password")
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", have.Value)
+	})
+}
+
+func TestClient_Request_compressed(t *testing.T) {
+	const data = `[{"pwr":350}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var wc io.WriteCloser
+		switch r.URL.Path {
+		case "/e":
+			w.Header().Set("Content-Encoding", "gzip")
+			wc = gzip.NewWriter(w)
+		default:
+			w.Header().Set("Content-Encoding", "deflate")
+			wc = zlib.NewWriter(w)
+		}
+		_, _ = wc.Write([]byte(data))
+		_ = wc.Close()
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	t.Run("gzip", func(t *testing.T) {
+		// prevent the transport from decompressing the response itself
+		c.client.Transport = &http.Transport{DisableCompression: true}
+		defer func() { c.client.Transport = nil }()
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+	})
+	t.Run("deflate", func(t *testing.T) {
+		var have []byte
+		assert.NoError(t, c.Request(context.Background(), "f", &have))
+		assert.Equal(t, data, string(have))
+	})
+}
+
+func TestClient_Request_maxResponseSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	t.Run("within limit", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithMaxResponseSize(13))
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+	})
+	t.Run("exceeds limit", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithMaxResponseSize(12))
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := NewClient(Config{BaseURL: srv.URL}, WithMaxResponseSize(0))
+		assert.ErrorIs(t, err, ErrInvalidMaxResponseSize)
+	})
+	t.Run("default", func(t *testing.T) {
+		var c Client
+		assert.Equal(t, int64(DefaultMaxResponseSize), c.MaxResponseSize())
+	})
+}
+
+func TestClient_withDefaultDeadline(t *testing.T) {
+	t.Run("zero timeout", func(t *testing.T) {
+		var c Client
+		start := time.Now()
+		ctx, cancel := c.withDefaultDeadline(context.Background())
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinRange(t, deadline, start.Add(DefaultTimeout), time.Now().Add(DefaultTimeout))
+	})
+	t.Run("call timeout", func(t *testing.T) {
+		c := Client{Config: Config{Timeout: time.Second}}
+		start := time.Now()
+		ctx, cancel := c.withDefaultDeadline(WithCallTimeout(context.Background(), time.Minute))
+		defer cancel()
+
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.WithinRange(t, deadline, start.Add(time.Minute), time.Now().Add(time.Minute))
+	})
+}
+
+func TestClient_Request_html(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("\n<html><head><title>YouLess</title></head><body>" +
+			strings.Repeat("x", 100) + "</body></html>"))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	_, err = c.GetMeterReading(context.Background())
+	assert.ErrorIs(t, err, ErrUnexpectedContentType)
+
+	var contentErr *UnexpectedContentError
+	assert.ErrorAs(t, err, &contentErr)
+	assert.Equal(t, "<html><head><title>YouLess</title></head><body>xxxxxxxxxxxxxxxxx...", contentErr.Body)
+}
+
+func TestWithLocation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/V" {
+			_, _ = w.Write([]byte(`{"un":"Watt","tm":"2024-01-28T12:00:00","dt":3600,"val":["350","120"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"gts":2401281200,"wts":2401281200}]`))
+	}))
+	defer srv.Close()
+
+	loc := time.FixedZone("CET", 60*60)
+	c, err := NewClient(Config{BaseURL: srv.URL}, WithLocation(loc))
+	assert.NoError(t, err)
+	assert.Same(t, loc, c.Location())
+
+	want := time.Date(2024, 1, 28, 11, 0, 0, 0, time.UTC)
+
+	have, err := c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, want, have.GasReading.Time().UTC())
+	assert.Equal(t, want, have.WaterReading.Time().UTC())
+
+	log, err := c.GetLog(context.Background(), Electricity, PerHour, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, want, log.Time().UTC())
+	values, err := log.TimedValues()
+	assert.NoError(t, err)
+	assert.Equal(t, want.Add(time.Hour), values[1].Time.UTC())
+
+	tm, err := c.ParseTimestamp(2401281200)
+	assert.NoError(t, err)
+	assert.Equal(t, want, tm.UTC())
+}
+
+func TestWithRequestHook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "abc", r.Header.Get("X-Correlation-ID"))
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: "tk", Value: "secret"})
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	var statuses []int
+	c, err := NewClient(Config{BaseURL: srv.URL, Password: "secret"},
+		WithRequestHook(func(req *http.Request) {
+			req.Header.Set("X-Correlation-ID", "abc")
+		}, func(res *http.Response, err error) {
+			assert.NoError(t, err)
+			statuses = append(statuses, res.StatusCode)
+		}),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{http.StatusFound, http.StatusOK}, statuses)
+}
+
+func TestWithRateLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL}, WithRateLimit(20, 1))
+	assert.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+	}
+	// the first request is allowed immediately, the others wait 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+
+	t.Run("context", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithRateLimit(0.1, 1))
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = c.GetMeterReading(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_Authorize_lockout(t *testing.T) {
+	tests := map[string]http.HandlerFunc{
+		"too many requests": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "300")
+			w.WriteHeader(http.StatusTooManyRequests)
+		},
+		"forbidden with body": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte("<html>Login locked, try again later</html>"))
+		},
+	}
+	for name, handler := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(handler)
+			defer srv.Close()
+
+			c, err := NewClient(Config{BaseURL: srv.URL})
+			assert.NoError(t, err)
+
+			_, err = c.Authorize(context.Background(), "wrong")
+			assert.ErrorIs(t, err, ErrAuthLockout)
+			assert.NotErrorIs(t, err, ErrInvalidPassword)
+
+			var lockout *AuthLockoutError
+			assert.ErrorAs(t, err, &lockout)
+			if name == "too many requests" {
+				assert.Equal(t, 5*time.Minute, lockout.RetryAfter)
+			}
+		})
+	}
+	t.Run("invalid password", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, err = c.Authorize(context.Background(), "wrong")
+		assert.ErrorIs(t, err, ErrInvalidPassword)
+	})
+}
+
+func TestClient_Authorize_groupWindow(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			n := posts.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: fmt.Sprintf("secret%d", n)})
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case r.URL.Path == "/e":
+			_, _ = w.Write([]byte(`[{"pwr":350}]`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL}, WithGroupWindow(time.Minute))
+	assert.NoError(t, err)
+
+	cookie, err := c.Authorize(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret1", cookie.Value)
+
+	_, err = c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, c.recent)
+
+	assert.NoError(t, c.InvalidateAuth())
+	assert.Empty(t, c.recent)
+
+	cookie, err = c.Authorize(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret2", cookie.Value)
+	assert.Equal(t, int32(2), posts.Load())
+}
+
+func TestWithManualAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "secret"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case r.URL.Path == "/e":
+			// a proxy which sets a cookie named like the auth cookie and
+			// redirects to the actual page
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "proxy"})
+			http.Redirect(w, r, "/proxied/e", http.StatusFound)
+		case r.URL.Path == "/proxied/e":
+			_, _ = w.Write([]byte(`[{"pwr":350}]`))
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"}, WithManualAuth())
+	assert.NoError(t, err)
+
+	have, err := c.AuthCookie(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, have, "must not authorize automatically")
+
+	reading, err := c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(350), reading.Power)
+	assert.Nil(t, c.cookie.Load(), "must not capture cookie of data request")
+
+	cookie, err := c.Authorize(context.Background(), "password")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", cookie.Value)
+
+	have, err = c.AuthCookie(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", have.Value)
+}
+
+func TestClient_InvalidateAuth(t *testing.T) {
+	var authorized, requested atomic.Int32
+	var valid atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			n := authorized.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: fmt.Sprintf("v%d", n)})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+
+		requested.Add(1)
+		cookie, err := r.Cookie(DefaultAuthCookieName)
+		if err != nil || cookie.Value != valid.Load().(string) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	reset := func(token string) {
+		authorized.Store(0)
+		requested.Store(0)
+		valid.Store(token)
+	}
+
+	t.Run("explicit", func(t *testing.T) {
+		reset("v1")
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, c.InvalidateAuth())
+		assert.Nil(t, c.cookie.Load())
+
+		valid.Store("v2")
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), authorized.Load())
+	})
+	t.Run("expired", func(t *testing.T) {
+		reset("v1")
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+
+		// the device no longer accepts the first cookie
+		valid.Store("v2")
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), authorized.Load())
+		assert.Equal(t, int32(3), requested.Load())
+	})
+	t.Run("retry once", func(t *testing.T) {
+		reset("never")
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, ErrPasswordRequired)
+		assert.Equal(t, int32(2), authorized.Load())
+		assert.Equal(t, int32(2), requested.Load())
+	})
+	t.Run("store", func(t *testing.T) {
+		reset("v1")
+		store := FileCookieStore(filepath.Join(t.TempDir(), "cookie.json"))
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"}, WithCookieStore(store))
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.NoError(t, c.InvalidateAuth())
+
+		stored, err := store.Load()
+		assert.NoError(t, err)
+		assert.False(t, cookieValid(stored))
+	})
+}
+
+func TestClient_Ping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "secret"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		if cookie, err := r.Cookie(DefaultAuthCookieName); err != nil || cookie.Value != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// not valid json, ping must not decode the response
+		_, _ = w.Write([]byte(`{"model":`))
+	}))
+	defer srv.Close()
+
+	t.Run("ok", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"})
+		assert.NoError(t, err)
+		assert.NoError(t, c.Ping(context.Background()))
+	})
+	t.Run("password required", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+		assert.ErrorIs(t, c.Ping(context.Background()), ErrPasswordRequired)
+	})
+	t.Run("unreachable", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: "http://127.0.0.1:1"})
+		assert.NoError(t, err)
+		assert.Error(t, c.Ping(context.Background()))
+	})
+}
+
+func TestClient_Request_defaultDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// both the authorization and the request itself fit within the
+		// timeout, combined they do not
+		time.Sleep(60 * time.Millisecond)
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "secret"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	t.Run("without deadline", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password", Timeout: 100 * time.Millisecond})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("with deadline", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL, Password: "password", Timeout: 100 * time.Millisecond})
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		_, err = c.GetMeterReading(ctx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestClient_Request_authRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/e":
+			// first hop sets the auth cookie
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "secret"})
+			http.Redirect(w, r, "/hop", http.StatusFound)
+		case "/hop":
+			http.Redirect(w, r, "/content", http.StatusFound)
+		case "/content":
+			if cookie, err := r.Cookie(DefaultAuthCookieName); err != nil || cookie.Value != "secret" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`[{"pwr":350}]`))
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("follow", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+		assert.Equal(t, "secret", c.cookie.Load().Value)
+	})
+	t.Run("stop", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithStopOnAuthRedirect())
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.Error(t, err)
+		assert.Equal(t, "secret", c.cookie.Load().Value)
+	})
+}
+
+func TestClient_Request_authRedirectOtherHost(t *testing.T) {
+	var leaked atomic.Bool
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie(DefaultAuthCookieName); err == nil {
+			leaked.Store(true)
+		}
+		if r.URL.Path == "/hop" {
+			http.Redirect(w, r, "/content", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "secret"})
+		http.Redirect(w, r, other.URL+"/hop", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	have, err := c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(350), have.Power)
+	assert.Equal(t, "secret", c.cookie.Load().Value)
+	assert.False(t, leaked.Load(), "auth cookie sent to other host")
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+// ConfigFromEnv returns a Config with values read from the environment
+// variables <PREFIX>_BASE_URL, <PREFIX>_NAME, <PREFIX>_TIMEOUT,
+// <PREFIX>_PASSWORD and <PREFIX>_PASSWORD_FILE. The prefix is omitted when
+// empty. Fields of which the variable is not set get their default value.
+// The returned Config is validated, see Config.Validate.
+func ConfigFromEnv(prefix string) (Config, error) {
+	if prefix != "" {
+		prefix = strings.ToUpper(prefix) + "_"
+	}
+	env := func(name, field string) string {
+		if v, ok := os.LookupEnv(prefix + name); ok {
+			return v
+		}
+		return configDefault(field)
+	}
+
+	conf := Config{
+		BaseURL:      env("BASE_URL", "BaseURL"),
+		Name:         env("NAME", "Name"),
+		Password:     env("PASSWORD", "Password"),
+		PasswordFile: env("PASSWORD_FILE", "PasswordFile"),
+	}
+	if v := env("TIMEOUT", "Timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return conf, errors.Wrap(errors.Wrap(err, ErrInvalidTimeout), ErrInvalidConfig)
+		}
+		conf.Timeout = d
+	}
+
+	if err := conf.Validate(); err != nil {
+		return conf, err
+	}
+	return conf, nil
+}
+
+// configDefault returns the value of the default tag of the Config field.
+func configDefault(field string) string {
+	f, _ := reflect.TypeOf(Config{}).FieldByName(field)
+	return f.Tag.Get("default")
+}
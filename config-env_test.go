@@ -0,0 +1,52 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		have, err := ConfigFromEnv("YOULESS_TEST")
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			BaseURL: "http://youless",
+			Name:    "YouLess",
+			Timeout: 5 * time.Second,
+		}, have)
+	})
+	t.Run("values", func(t *testing.T) {
+		t.Setenv("YOULESS_TEST_BASE_URL", "http://192.168.1.10")
+		t.Setenv("YOULESS_TEST_NAME", "meterkast")
+		t.Setenv("YOULESS_TEST_TIMEOUT", "10s")
+		t.Setenv("YOULESS_TEST_PASSWORD", "secret")
+		t.Setenv("YOULESS_TEST_PASSWORD_FILE", "/run/secrets/youless")
+
+		have, err := ConfigFromEnv("youless_test")
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			BaseURL:      "http://192.168.1.10",
+			Name:         "meterkast",
+			Timeout:      10 * time.Second,
+			Password:     "secret",
+			PasswordFile: "/run/secrets/youless",
+		}, have)
+	})
+	t.Run("invalid timeout", func(t *testing.T) {
+		t.Setenv("YOULESS_TEST_TIMEOUT", "soon")
+		_, err := ConfigFromEnv("YOULESS_TEST")
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+		assert.ErrorIs(t, err, ErrInvalidTimeout)
+	})
+	t.Run("invalid base url", func(t *testing.T) {
+		t.Setenv("YOULESS_TEST_BASE_URL", "youless")
+		_, err := ConfigFromEnv("YOULESS_TEST")
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+}
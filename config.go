@@ -5,6 +5,7 @@
 package youless
 
 import (
+	urlpkg "net/url"
 	"strings"
 	"time"
 
@@ -14,8 +15,12 @@ import (
 const (
 	ErrInvalidBaseURL errors.Msg = "invalid base url"
 	ErrInvalidConfig  errors.Msg = "invalid config"
+	ErrInvalidTimeout errors.Msg = "timeout cannot be negative"
 )
 
+// DefaultTimeout is the timeout used for requests when Config.Timeout is zero.
+const DefaultTimeout = 30 * time.Second
+
 // Config is the configuration for a Client. It can be unmarshalled from json,
 // yaml, env or flag values.
 type Config struct {
@@ -23,8 +28,9 @@ type Config struct {
 	BaseURL string `json:"base_url" yaml:"baseUrl" default:"http://youless"`
 	// Name of the device, is optional and used for logging/debugging.
 	Name string `json:"name" yaml:"name" default:"YouLess"`
-	// Timeout specifies a time limit for requests made by the http.Client used
-	// by Client.
+	// Timeout specifies a time limit for requests made by Client. An earlier
+	// deadline of the request's context takes precedence. A zero Timeout
+	// means DefaultTimeout is used, a Client never waits indefinitely.
 	Timeout time.Duration `json:"timeout" yaml:"timeout" default:"5s"`
 	// Password used to connect with the device.
 	Password string `json:"password" yaml:"password"`
@@ -33,9 +39,36 @@ type Config struct {
 	PasswordFile string `json:"password_file" yaml:"passwordFile"`
 }
 
+// Validate returns an ErrInvalidConfig error when the Config is not valid.
+// BaseURL must be an absolute http(s) url without path or query, a trailing
+// slash is allowed. Timeout cannot be negative.
 func (c Config) Validate() error {
-	if c.BaseURL == "" {
-		return errors.Wrap(ErrInvalidBaseURL, ErrInvalidConfig)
+	if err := validateBaseURL(c.BaseURL); err != nil {
+		return errors.Wrap(err, ErrInvalidConfig)
+	}
+	if c.Timeout < 0 {
+		return errors.Wrap(errors.New(ErrInvalidTimeout), ErrInvalidConfig)
+	}
+	return nil
+}
+
+func validateBaseURL(baseURL string) error {
+	if baseURL == "" {
+		return errors.New(ErrInvalidBaseURL)
+	}
+
+	u, err := urlpkg.Parse(baseURL)
+	if err != nil {
+		return errors.Wrap(err, ErrInvalidBaseURL)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Wrap(errors.Newf("unsupported scheme %q", u.Scheme), ErrInvalidBaseURL)
+	}
+	if u.Host == "" {
+		return errors.Wrap(errors.New("missing host"), ErrInvalidBaseURL)
+	}
+	if strings.TrimSuffix(u.Path, "/") != "" || u.RawQuery != "" || u.Fragment != "" {
+		return errors.Wrap(errors.New("must not contain a path, query or fragment"), ErrInvalidBaseURL)
 	}
 	return nil
 }
@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tests := []string{
+			"http://youless",
+			"http://youless/",
+			"https://192.168.1.10",
+			"http://192.168.1.10:8080/",
+		}
+		for _, baseURL := range tests {
+			t.Run(baseURL, func(t *testing.T) {
+				assert.NoError(t, Config{BaseURL: baseURL}.Validate())
+			})
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		tests := []string{
+			"",
+			"youless",
+			"htp://youless",
+			"http://",
+			"http://youless/e",
+			"http://youless?f=j",
+			"http://you less",
+		}
+		for _, baseURL := range tests {
+			t.Run(baseURL, func(t *testing.T) {
+				err := Config{BaseURL: baseURL}.Validate()
+				assert.ErrorIs(t, err, ErrInvalidConfig)
+				assert.ErrorIs(t, err, ErrInvalidBaseURL)
+			})
+		}
+	})
+}
+
+func TestConfig_Validate_timeout(t *testing.T) {
+	err := Config{BaseURL: "http://youless", Timeout: -time.Second}.Validate()
+	assert.ErrorIs(t, err, ErrInvalidConfig)
+	assert.ErrorIs(t, err, ErrInvalidTimeout)
+
+	assert.NoError(t, Config{BaseURL: "http://youless"}.Validate())
+}
+
+func TestConfig_url(t *testing.T) {
+	assert.Equal(t, "http://youless/e", Config{BaseURL: "http://youless"}.url("e"))
+	assert.Equal(t, "http://youless/e", Config{BaseURL: "http://youless/"}.url("e"))
+}
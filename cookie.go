@@ -0,0 +1,91 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrLoadAuthCookie errors.Msg = "failed to load auth cookie"
+	ErrSaveAuthCookie errors.Msg = "failed to save auth cookie"
+)
+
+// CookieStore stores the auth cookie so it can be reused across process
+// restarts.
+type CookieStore interface {
+	// Load returns the stored http.Cookie, or nil when no cookie is stored.
+	Load() (*http.Cookie, error)
+	// Save stores the http.Cookie.
+	Save(cookie *http.Cookie) error
+}
+
+// FileCookieStore returns a CookieStore which stores the auth cookie as json in
+// the file at path.
+func FileCookieStore(path string) CookieStore { return &fileCookieStore{path} }
+
+type fileCookieStore struct{ path string }
+
+type storedCookie struct {
+	Name    string    `json:"name"`
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires,omitempty"`
+}
+
+func (s *fileCookieStore) Load() (*http.Cookie, error) {
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.WithStack(err)
+	}
+
+	var v storedCookie
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &http.Cookie{
+		Name:    v.Name,
+		Value:   v.Value,
+		Expires: v.Expires,
+	}, nil
+}
+
+func (s *fileCookieStore) Save(cookie *http.Cookie) error {
+	b, err := json.Marshal(storedCookie{
+		Name:    cookie.Name,
+		Value:   cookie.Value,
+		Expires: cookie.Expires,
+	})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = os.WriteFile(s.path, b, 0600); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// cookieExpires sets the Expires field of cookie when only its MaxAge is
+// set, so the expiry is retained when the cookie is stored.
+func cookieExpires(cookie *http.Cookie) {
+	if cookie.Expires.IsZero() && cookie.MaxAge > 0 {
+		cookie.Expires = time.Now().Add(time.Duration(cookie.MaxAge) * time.Second)
+	}
+}
+
+// cookieValid indicates if cookie is not nil and not yet expired.
+func cookieValid(cookie *http.Cookie) bool {
+	if cookie == nil || cookie.Value == "" {
+		return false
+	}
+	return cookie.Expires.IsZero() || time.Now().Before(cookie.Expires)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCookieStore(t *testing.T) {
+	store := FileCookieStore(filepath.Join(t.TempDir(), "cookie.json"))
+
+	t.Run("empty", func(t *testing.T) {
+		have, err := store.Load()
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+	t.Run("save and load", func(t *testing.T) {
+		want := &http.Cookie{
+			Name:    "tk",
+			Value:   "secret",
+			Expires: time.Date(2030, 1, 28, 12, 0, 0, 0, time.UTC),
+		}
+		assert.NoError(t, store.Save(want))
+
+		have, err := store.Load()
+		assert.NoError(t, err)
+		assert.Equal(t, want.Name, have.Name)
+		assert.Equal(t, want.Value, have.Value)
+		assert.True(t, want.Expires.Equal(have.Expires))
+	})
+}
+
+func TestClient_AuthCookie(t *testing.T) {
+	t.Run("stored cookie", func(t *testing.T) {
+		store := FileCookieStore(filepath.Join(t.TempDir(), "cookie.json"))
+		want := &http.Cookie{Name: "tk", Value: "secret"}
+		assert.NoError(t, store.Save(want))
+
+		c, err := NewClient(Config{Password: "pass"}, WithCookieStore(store))
+		assert.NoError(t, err)
+
+		have, err := c.AuthCookie(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, want.Value, have.Value)
+	})
+	t.Run("expired stored cookie", func(t *testing.T) {
+		store := FileCookieStore(filepath.Join(t.TempDir(), "cookie.json"))
+		assert.NoError(t, store.Save(&http.Cookie{
+			Name:    "tk",
+			Value:   "expired",
+			Expires: time.Now().Add(-time.Minute),
+		}))
+
+		c, err := NewClient(Config{}, WithCookieStore(store))
+		assert.NoError(t, err)
+
+		have, err := c.AuthCookie(context.Background())
+		assert.NoError(t, err)
+		assert.Nil(t, have)
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+var csvHeader = []string{"time", "value", "unit", "inactive"}
+
+// WriteCSV writes the TimedValues of the LogResponse as csv to w. The first
+// row is a header with columns time, value, unit and inactive. Times are
+// formatted as RFC3339, the value of an inactive sample is left blank.
+func (r LogResponse) WriteCSV(w io.Writer) error {
+	return WriteCSV(w, r)
+}
+
+// WriteCSV writes the TimedValues of multiple LogResponses, e.g. multiple
+// pages of the same log, as a single csv to w. The rows are sorted by time,
+// oldest first. See LogResponse.WriteCSV for the format.
+func WriteCSV(w io.Writer, logs ...LogResponse) error {
+	type row struct {
+		unit Unit
+		TimedValue
+	}
+
+	var rows []row
+	for _, l := range logs {
+		values, err := l.TimedValues()
+		if err != nil {
+			return err
+		}
+		for _, tv := range values {
+			rows = append(rows, row{unit: l.Unit, TimedValue: tv})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Time.Before(rows[j].Time)
+	})
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return errors.WithStack(err)
+	}
+	for _, r := range rows {
+		var value string
+		if !r.Inactive {
+			value = strconv.FormatInt(r.Value, 10)
+		}
+		if err := cw.Write([]string{
+			r.Time.Format(time.RFC3339),
+			value,
+			string(r.unit),
+			strconv.FormatBool(r.Inactive),
+		}); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
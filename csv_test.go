@@ -0,0 +1,58 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogResponse_WriteCSV(t *testing.T) {
+	var buf strings.Builder
+	assert.NoError(t, LogResponse{
+		Unit:      Watt,
+		Timestamp: "2024-01-28T12:00:00",
+		Interval:  PerHour,
+		RawValues: []string{"350", "*", "400", ""},
+		loc:       time.UTC,
+	}.WriteCSV(&buf))
+
+	assert.Equal(t, "time,value,unit,inactive\n"+
+		"2024-01-28T12:00:00Z,350,Watt,false\n"+
+		"2024-01-28T13:00:00Z,,Watt,true\n"+
+		"2024-01-28T14:00:00Z,400,Watt,false\n",
+		buf.String(),
+	)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf strings.Builder
+	assert.NoError(t, WriteCSV(&buf,
+		LogResponse{
+			Unit:      Watt,
+			Timestamp: "2024-01-28T14:00:00",
+			Interval:  PerHour,
+			RawValues: []string{"500", ""},
+			loc:       time.UTC,
+		},
+		LogResponse{
+			Unit:      Watt,
+			Timestamp: "2024-01-28T12:00:00",
+			Interval:  PerHour,
+			RawValues: []string{"350", "400"},
+			loc:       time.UTC,
+		},
+	))
+
+	assert.Equal(t, "time,value,unit,inactive\n"+
+		"2024-01-28T12:00:00Z,350,Watt,false\n"+
+		"2024-01-28T13:00:00Z,400,Watt,false\n"+
+		"2024-01-28T14:00:00Z,500,Watt,false\n",
+		buf.String(),
+	)
+}
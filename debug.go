@@ -0,0 +1,97 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+)
+
+const redacted = "REDACTED"
+
+var (
+	// redactHeaders matches the header lines which contain credentials
+	redactHeaders = regexp.MustCompile(`(?mi)^(Cookie|Authorization|Set-Cookie):[^\r\n]*`)
+	// redactPassword matches the password form value sent by Authorize
+	redactPassword = regexp.MustCompile(`(^|&)w=[^&]*`)
+)
+
+// dumpTransport is a http.RoundTripper which writes a redacted dump of all
+// requests and responses to w. At most limit bytes of a response body are
+// dumped.
+type dumpTransport struct {
+	rt    http.RoundTripper
+	mut   sync.Mutex
+	w     io.Writer
+	limit func() int64
+}
+
+func (t *dumpTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt := t.rt
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+
+	if b, err := httputil.DumpRequestOut(req, true); err == nil {
+		t.write(redactDump(b, true))
+	}
+
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		return res, err
+	}
+
+	b, err := httputil.DumpResponse(res, false)
+	if err != nil {
+		return res, nil
+	}
+
+	// only read the part of the body which is dumped, the remainder is left
+	// for the caller to read
+	limit := t.limit()
+	head, err := io.ReadAll(io.LimitReader(res.Body, limit+1))
+	res.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(head), res.Body),
+		Closer: res.Body,
+	}
+	if err == nil {
+		if int64(len(head)) > limit {
+			head = append(head[:limit:limit], "\n[body truncated]"...)
+		}
+		b = append(b, head...)
+	}
+	t.write(redactDump(b, false))
+	return res, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+func (t *dumpTransport) write(b []byte) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	_, _ = t.w.Write(b)
+	_, _ = t.w.Write([]byte("\n"))
+}
+
+// redactDump removes credentials from the headers of dump b and, when it is
+// a request, from its form body.
+func redactDump(b []byte, isRequest bool) []byte {
+	head, body, found := bytes.Cut(b, []byte("\r\n\r\n"))
+	head = redactHeaders.ReplaceAll(head, []byte("${1}: "+redacted))
+	if !found {
+		return head
+	}
+	if isRequest {
+		body = redactPassword.ReplaceAll(body, []byte("${1}w="+redacted))
+	}
+	return bytes.Join([][]byte{head, body}, []byte("\r\n\r\n"))
+}
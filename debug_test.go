@@ -0,0 +1,90 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDebugDump(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "s3cr3t-cookie"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	c, err := NewClient(Config{BaseURL: srv.URL, Password: "s3cr3t-password"}, WithDebugDump(&buf))
+	assert.NoError(t, err)
+
+	have, err := c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(350), have.Power)
+
+	dump := buf.String()
+	assert.Contains(t, dump, "POST / HTTP/1.1")
+	assert.Contains(t, dump, "GET /e HTTP/1.1")
+	assert.Contains(t, dump, `[{"pwr":350}]`)
+	assert.Contains(t, dump, "w="+redacted)
+	assert.Contains(t, dump, "Set-Cookie: "+redacted)
+	assert.Contains(t, dump, "Cookie: "+redacted)
+	assert.NotContains(t, dump, "s3cr3t")
+
+	t.Run("truncated", func(t *testing.T) {
+		var buf bytes.Buffer
+		c, err := NewClient(Config{BaseURL: srv.URL},
+			WithDebugDump(&buf),
+			WithMaxResponseSize(4),
+		)
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+		assert.Contains(t, buf.String(), "\r\n\r\n[{\"p\n[body truncated]")
+	})
+}
+
+func TestRedactDump(t *testing.T) {
+	have := redactDump([]byte("POST / HTTP/1.1\r\nHost: youless\r\nAuthorization: Basic dXNlcjpwYXNz\r\n\r\nfoo=bar&w=secret"), true)
+	assert.Equal(t, "POST / HTTP/1.1\r\nHost: youless\r\nAuthorization: "+redacted+"\r\n\r\nfoo=bar&w="+redacted, string(have))
+}
+
+func TestClient_addTransportWrapper(t *testing.T) {
+	var order []string
+	wrapper := func(name string) func(rt http.RoundTripper) http.RoundTripper {
+		return func(rt http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, name)
+				return rt.RoundTrip(req)
+			})
+		}
+	}
+
+	var c Client
+	c.addTransportWrapper(wrapper("first"))
+	c.addTransportWrapper(wrapper("second"))
+	assert.NoError(t, WithTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		order = append(order, "transport")
+		return nil, http.ErrNotSupported
+	}))(&c))
+
+	req, _ := http.NewRequest(http.MethodGet, "http://youless/", nil)
+	_, _ = c.client.Transport.RoundTrip(req)
+	assert.Equal(t, []string{"second", "first", "transport"}, order)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return fn(req) }
@@ -0,0 +1,98 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/go-pogo/errors"
+)
+
+// DecodeMeterReading decodes the json response of the device's meter reading
+// page, as read from r, into a MeterReadingResponse. Both the array of the
+// enologic firmware and the single object of the legacy firmware are
+// supported. It returns an ErrEmptyResponse error when the response does not
+// contain a reading.
+func DecodeMeterReading(r io.Reader) (MeterReadingResponse, error) {
+	var res []MeterReadingResponse
+	if err := decodeFrom(r, &res); err != nil {
+		return MeterReadingResponse{}, err
+	}
+	return firstMeterReading(res)
+}
+
+// DecodePhaseReading decodes the json response of the device's phase reading
+// page, as read from r, into a PhaseReadingResponse.
+func DecodePhaseReading(r io.Reader) (PhaseReadingResponse, error) {
+	var res PhaseReadingResponse
+	err := decodeFrom(r, &res)
+	return res, err
+}
+
+// DecodeLog decodes the json response of any of the device's log pages, as
+// read from r, into a LogResponse.
+func DecodeLog(r io.Reader) (LogResponse, error) {
+	var res LogResponse
+	err := decodeFrom(r, &res)
+	return res, err
+}
+
+func decodeFrom(r io.Reader, out any) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return decode(b, out)
+}
+
+// decode unmarshals the json data b into out. It returns an
+// ErrUnexpectedContentType error when b is html instead of json. Meter
+// readings of both enologic and legacy firmware are decoded into a
+// *[]MeterReadingResponse, see decodeMeterReadings.
+func decode(b []byte, out any) error {
+	if isHTML(b) {
+		// the device sometimes responds with its web interface instead of
+		// the requested json data
+		return errors.Wrap(&UnexpectedContentError{
+			Body: truncate(b, unexpectedContentLen),
+		}, ErrUnexpectedContentType)
+	}
+	if res, ok := out.(*[]MeterReadingResponse); ok {
+		return decodeMeterReadings(b, res)
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// decodeMeterReadings decodes the response of the meter reading page. The
+// enologic firmware (e.g. LS120) responds with an array containing a single
+// reading, the legacy firmware (e.g. LS110) with just the reading object.
+func decodeMeterReadings(data []byte, out *[]MeterReadingResponse) error {
+	if data = bytes.TrimSpace(data); len(data) != 0 && data[0] == '{' {
+		var res MeterReadingResponse
+		if err := json.Unmarshal(data, &res); err != nil {
+			return errors.WithStack(err)
+		}
+		*out = []MeterReadingResponse{res}
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+func firstMeterReading(res []MeterReadingResponse) (MeterReadingResponse, error) {
+	if len(res) == 0 {
+		// the device returns an empty array when it is not yet ready, e.g.
+		// during boot
+		return MeterReadingResponse{}, errors.New(ErrEmptyResponse)
+	}
+	return res[0], nil
+}
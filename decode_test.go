@@ -0,0 +1,61 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeMeterReading(t *testing.T) {
+	t.Run("unwrap", func(t *testing.T) {
+		have, err := DecodeMeterReading(strings.NewReader(`[{"pwr":350,"gas":1.234}]`))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+		assert.Equal(t, 1.234, have.GasTotal)
+	})
+	t.Run("object", func(t *testing.T) {
+		have, err := DecodeMeterReading(strings.NewReader(` {"pwr":350,"gas":1.234}`))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+		assert.Equal(t, 1.234, have.GasTotal)
+	})
+	t.Run("empty", func(t *testing.T) {
+		_, err := DecodeMeterReading(strings.NewReader(`[]`))
+		assert.ErrorIs(t, err, ErrEmptyResponse)
+	})
+	t.Run("html", func(t *testing.T) {
+		_, err := DecodeMeterReading(strings.NewReader(`<!DOCTYPE html><html></html>`))
+		assert.ErrorIs(t, err, ErrUnexpectedContentType)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := DecodeMeterReading(strings.NewReader(`{`))
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodePhaseReading(t *testing.T) {
+	have, err := DecodePhaseReading(strings.NewReader(`{"tr":2,"i1":1.52,"v1":231.2,"l1":350}`))
+	assert.NoError(t, err)
+	assert.Equal(t, PhaseReadingResponse{
+		Tariff:   2,
+		Current1: 1.52,
+		Power1:   350,
+		Voltage1: 231.2,
+	}, have)
+}
+
+func TestDecodeLog(t *testing.T) {
+	have, err := DecodeLog(strings.NewReader(`{"un":"Watt","tm":"2024-01-28T12:00:00","dt":600,"val":["120",""]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, LogResponse{
+		Unit:      Watt,
+		Timestamp: "2024-01-28T12:00:00",
+		Interval:  Per10min,
+		RawValues: []string{"120", ""},
+	}, have)
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+// Direction indicates whether electricity is imported from, or exported to,
+// the grid.
+type Direction int8
+
+const (
+	Idle Direction = iota
+	Import
+	Export
+)
+
+// IdleThreshold is the power in Watt, in either direction, up to which the
+// electricity flow is considered Idle. This prevents a meter which fluctuates
+// around zero from constantly switching between Import and Export.
+const IdleThreshold int64 = 5
+
+// The String representation of Direction.
+func (d Direction) String() string {
+	switch d {
+	case Import:
+		return "import"
+	case Export:
+		return "export"
+	default:
+		return "idle"
+	}
+}
+
+// Direction returns the Direction of the current electricity flow based on
+// Power and IdleThreshold.
+func (r ElectricityReading) Direction() Direction {
+	switch {
+	case r.Power > IdleThreshold:
+		return Import
+	case r.Power < -IdleThreshold:
+		return Export
+	default:
+		return Idle
+	}
+}
+
+// IsImporting indicates if electricity is imported from the grid.
+func (r ElectricityReading) IsImporting() bool { return r.Direction() == Import }
+
+// IsExporting indicates if electricity is exported to the grid.
+func (r ElectricityReading) IsExporting() bool { return r.Direction() == Export }
+
+// AbsPower returns the absolute value of Power in Watt, regardless of its
+// Direction.
+func (r ElectricityReading) AbsPower() int64 {
+	if r.Power < 0 {
+		return -r.Power
+	}
+	return r.Power
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElectricityReading_Direction(t *testing.T) {
+	tests := map[int64]Direction{
+		350:  Import,
+		6:    Import,
+		5:    Idle,
+		0:    Idle,
+		-5:   Idle,
+		-6:   Export,
+		-350: Export,
+	}
+	for pwr, want := range tests {
+		t.Run(strconv.FormatInt(pwr, 10), func(t *testing.T) {
+			r := ElectricityReading{Power: pwr}
+			assert.Equal(t, want, r.Direction())
+			assert.Equal(t, want == Import, r.IsImporting())
+			assert.Equal(t, want == Export, r.IsExporting())
+		})
+	}
+}
+
+func TestElectricityReading_AbsPower(t *testing.T) {
+	assert.Equal(t, int64(350), ElectricityReading{Power: 350}.AbsPower())
+	assert.Equal(t, int64(350), ElectricityReading{Power: -350}.AbsPower())
+}
+
+func TestDirection_String(t *testing.T) {
+	assert.Equal(t, "idle", Idle.String())
+	assert.Equal(t, "import", Import.String())
+	assert.Equal(t, "export", Export.String())
+}
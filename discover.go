@@ -0,0 +1,155 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	urlpkg "net/url"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrNoDeviceFound errors.Msg = "no device found"
+
+	ssdpAddr     = "239.255.255.250:1900"
+	ssdpMSearch  = "M-SEARCH * HTTP/1.1\r\nHOST: " + ssdpAddr + "\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: upnp:rootdevice\r\n\r\n"
+	discoverWait = 2 * time.Second
+)
+
+// DiscoveredDevice is a YouLess device found on the local network by Discover.
+type DiscoveredDevice struct {
+	// BaseURL of the device, can be used as Config.BaseURL.
+	BaseURL string
+	// Model of the device.
+	Model string
+	// MAC address of the device.
+	MAC string
+}
+
+// Discover broadcasts an SSDP M-SEARCH request on the local network and
+// returns all YouLess devices that respond to it. Each responder is verified
+// by requesting its device info, responders that are not a YouLess device
+// are ignored. Discover listens for responses for at most 2 seconds, or less
+// when ctx is done earlier.
+func Discover(ctx context.Context) ([]DiscoveredDevice, error) {
+	var res []DiscoveredDevice
+	err := discover(ctx, func(dev DiscoveredDevice) bool {
+		res = append(res, dev)
+		return true
+	})
+	return res, err
+}
+
+// DiscoverOne is similar to Discover, except it returns the first YouLess
+// device that responds. It returns an ErrNoDeviceFound error when no device
+// responded in time.
+func DiscoverOne(ctx context.Context) (DiscoveredDevice, error) {
+	var res DiscoveredDevice
+	err := discover(ctx, func(dev DiscoveredDevice) bool {
+		res = dev
+		return false
+	})
+	if err != nil {
+		return res, err
+	}
+	if res.BaseURL == "" {
+		return res, errors.New(ErrNoDeviceFound)
+	}
+	return res, nil
+}
+
+func discover(ctx context.Context, fn func(dev DiscoveredDevice) bool) error {
+	conn, err := net.ListenPacket("udp4", ":0")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	listenCtx, cancel := context.WithTimeout(ctx, discoverWait)
+	defer cancel()
+	stop := context.AfterFunc(listenCtx, func() {
+		_ = conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	dst, err := net.ResolveUDPAddr("udp4", ssdpAddr)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if _, err = conn.WriteTo([]byte(ssdpMSearch), dst); err != nil {
+		return errors.WithStack(err)
+	}
+
+	seen := make(map[string]struct{})
+	buf := make([]byte, 2048)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if listenCtx.Err() != nil {
+				// stopped listening because ctx is done or wait time elapsed
+				return nil
+			}
+			return errors.WithStack(err)
+		}
+
+		baseURL, ok := parseSSDPResponse(buf[:n])
+		if !ok {
+			continue
+		}
+		if _, ok = seen[baseURL]; ok {
+			continue
+		}
+		seen[baseURL] = struct{}{}
+
+		dev, ok := probeDevice(ctx, baseURL)
+		if ok && !fn(dev) {
+			return nil
+		}
+	}
+}
+
+// parseSSDPResponse parses the SSDP response and returns the base url of the
+// responder, derived from its LOCATION header.
+func parseSSDPResponse(b []byte) (string, bool) {
+	res, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(b)), nil)
+	if err != nil {
+		return "", false
+	}
+	_ = res.Body.Close()
+
+	loc, err := urlpkg.Parse(res.Header.Get("Location"))
+	if err != nil || loc.Host == "" {
+		return "", false
+	}
+	return loc.Scheme + "://" + loc.Host, true
+}
+
+// probeDevice requests the device info of the responder at baseURL to verify
+// it is a YouLess device.
+func probeDevice(ctx context.Context, baseURL string) (DiscoveredDevice, bool) {
+	c, err := NewClient(Config{
+		BaseURL: baseURL,
+		Timeout: discoverWait,
+	})
+	if err != nil {
+		return DiscoveredDevice{}, false
+	}
+
+	info, err := c.GetDeviceInfo(ctx)
+	if err != nil || info.Model == "" {
+		return DiscoveredDevice{}, false
+	}
+	return DiscoveredDevice{
+		BaseURL: baseURL,
+		Model:   info.Model,
+		MAC:     info.MAC,
+	}, true
+}
@@ -0,0 +1,31 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSSDPResponse(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		have, ok := parseSSDPResponse([]byte("HTTP/1.1 200 OK\r\n" +
+			"CACHE-CONTROL: max-age=1800\r\n" +
+			"LOCATION: http://192.168.1.10:80/description.xml\r\n" +
+			"ST: upnp:rootdevice\r\n" +
+			"\r\n"))
+		assert.True(t, ok)
+		assert.Equal(t, "http://192.168.1.10:80", have)
+	})
+	t.Run("missing location", func(t *testing.T) {
+		_, ok := parseSSDPResponse([]byte("HTTP/1.1 200 OK\r\nST: upnp:rootdevice\r\n\r\n"))
+		assert.False(t, ok)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, ok := parseSSDPResponse([]byte("garbage"))
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+// maxEnergyPages is the maximum amount of log pages requested to collect all
+// values since the start of the day or month.
+const maxEnergyPages = 3
+
+// EnergyToday returns the total energy (kWh) or volume (m3) of Utility u since
+// the start of the current day. The current day is determined by the time of
+// the most recent value in the device's log, not the local clock.
+func (api *apiRequester) EnergyToday(ctx context.Context, u Utility) (float64, error) {
+	return api.energySince(ctx, u, PerHour, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	})
+}
+
+// EnergyThisMonth returns the total energy (kWh) or volume (m3) of Utility u
+// since the start of the current month. The current month is determined by the
+// time of the most recent value in the device's log, not the local clock.
+func (api *apiRequester) EnergyThisMonth(ctx context.Context, u Utility) (float64, error) {
+	return api.energySince(ctx, u, PerDay, func(t time.Time) time.Time {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	})
+}
+
+func (api *apiRequester) energySince(ctx context.Context, u Utility, i Interval, start func(now time.Time) time.Time) (float64, error) {
+	var since time.Time
+	var total float64
+	var unit Unit
+
+	// page 1 contains the most recent values, request older pages until the
+	// start of the period is reached
+	for page := uint(1); page <= maxEnergyPages; page++ {
+		var res LogResponse
+		var err error
+		if page == 1 {
+			res, err = api.GetLatestLog(ctx, u, i)
+		} else {
+			res, err = api.GetLog(ctx, u, i, page)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if page == 1 {
+			now, ok := res.lastTime()
+			if !ok {
+				return 0, nil
+			}
+			since = start(now)
+			unit = res.Unit
+		} else if err = sameUnit(unit, res.Unit); err != nil {
+			// do not mix pages of which the unit changed, e.g. after a
+			// firmware upgrade
+			return 0, err
+		}
+
+		sum, err := res.sumSince(since)
+		if err != nil {
+			return 0, err
+		}
+		total += sum
+
+		// a PerDay page contains all values of a single month, older pages
+		// are not indexed by age
+		if i == PerDay || !res.Time().After(since) {
+			break
+		}
+	}
+	return total, nil
+}
+
+// lastTime returns the time of the last active value.
+func (r LogResponse) lastTime() (time.Time, bool) {
+	for i := len(r.RawValues) - 1; i >= 0; i-- {
+		if v := strings.TrimSpace(r.RawValues[i]); v != "" && v != "*" {
+			return r.TimeOfValue(uint(i)), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sumSince sums all active values at or after since. Power values in Watt are
+// integrated to kWh using TotalEnergy, other values are converted to kWh or m3
+// depending on the LogResponse's Unit. Values may contain a comma as decimal
+// separator.
+func (r LogResponse) sumSince(since time.Time) (float64, error) {
+	r = r.since(since)
+	if r.Unit == Watt {
+		return r.TotalEnergy()
+	}
+
+	var factor float64
+	switch r.Unit {
+	case Liter:
+		factor = 1.0 / 1000
+	case KWh, CubicMeter:
+		factor = 1
+	default:
+		return 0, errors.Wrapf(ErrIncompatibleUnits, "cannot sum %s", r.Unit)
+	}
+
+	var sum float64
+	for _, v := range r.RawValues {
+		if v = strings.TrimSpace(v); v == "" || v == "*" {
+			continue
+		}
+
+		n, err := parseDecimal(v)
+		if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		sum += n
+	}
+	return sum * factor, nil
+}
+
+// since returns a copy of the LogResponse of which all values before t are
+// marked inactive.
+func (r LogResponse) since(t time.Time) LogResponse {
+	values := make([]string, len(r.RawValues))
+	for i, v := range r.RawValues {
+		if v != "" && r.TimeOfValue(uint(i)).Before(t) {
+			v = "*"
+		}
+		values[i] = v
+	}
+	r.RawValues = values
+	return r
+}
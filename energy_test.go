@@ -0,0 +1,73 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_EnergyToday(t *testing.T) {
+	pages := map[string]string{
+		// starts at 20:00 yesterday, ends at 03:00 today
+		"V?d=1&f=j": `{"un":"Watt","tm":"2024-01-27T20:00:00","dt":3600,"val":["1000","1000","1000","1000","500","1500","*","2000",""]}`,
+	}
+	api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+		data, ok := pages[path]
+		if !ok {
+			t.Fatalf("unexpected request to %s", path)
+		}
+		return json.Unmarshal([]byte(data), out)
+	})}
+
+	have, err := api.EnergyToday(context.Background(), Electricity)
+	assert.NoError(t, err)
+	// 500 + 1500 + 2000 W over 1 hour each = 4 kWh
+	assert.InDelta(t, 4.0, have, 1e-9)
+}
+
+func TestAPIRequester_EnergyToday_unitMismatch(t *testing.T) {
+	pages := map[string]string{
+		"V?d=1&f=j": `{"un":"Watt","tm":"2024-01-28T01:00:00","dt":3600,"val":["1000","2000",""]}`,
+		// older page, written before a firmware upgrade changed the unit
+		"V?d=2&f=j": `{"un":"kWh","tm":"2024-01-27T16:00:00","dt":3600,"val":["1","1","1","1","1","1","1","1","1"]}`,
+	}
+	api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+		data, ok := pages[path]
+		if !ok {
+			t.Fatalf("unexpected request to %s", path)
+		}
+		return json.Unmarshal([]byte(data), out)
+	})}
+
+	_, err := api.EnergyToday(context.Background(), Electricity)
+	assert.ErrorIs(t, err, ErrUnitMismatch)
+
+	var mismatch *UnitMismatchError
+	assert.ErrorAs(t, err, &mismatch)
+	assert.Equal(t, &UnitMismatchError{Want: Watt, Have: KWh}, mismatch)
+}
+
+func TestAPIRequester_EnergyThisMonth(t *testing.T) {
+	now := time.Now()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	page := fmt.Sprintf("W?m=%d&f=j", now.Month())
+
+	api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+		if path != page {
+			t.Fatalf("unexpected request to %s", path)
+		}
+		return json.Unmarshal([]byte(`{"un":"m3","tm":"`+month.Format(LogTimeLayout)+`","dt":86400,"val":["1,5","2,25","0,75","",""]}`), out)
+	})}
+
+	have, err := api.EnergyThisMonth(context.Background(), Gas)
+	assert.NoError(t, err)
+	assert.InDelta(t, 4.5, have, 1e-9)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-pogo/errors"
+)
+
+// Sentinel errors which match errors containing an HTTPStatusError or
+// UnexpectedResponseError with a status code of their kind, using errors.Is.
+// For example, errors.Is(err, ErrForbidden) reports whether the device
+// responded with 403 Forbidden, and errors.Is(err, ErrServerStatus) whether it
+// responded with any 5xx status code.
+const (
+	ErrUnauthorized errors.Msg = "unauthorized"
+	ErrForbidden    errors.Msg = "forbidden"
+	ErrNotFound     errors.Msg = "not found"
+	ErrClientStatus errors.Msg = "client error status"
+	ErrServerStatus errors.Msg = "server error status"
+)
+
+// HTTPStatusError contains the status code of a response which the Client
+// handles as a known error, e.g. a 403 Forbidden response which results in an
+// ErrPasswordRequired error. Use errors.Is with one of the sentinel errors
+// (ErrUnauthorized, ErrForbidden, ErrNotFound, ErrClientStatus,
+// ErrServerStatus) to match its status code.
+type HTTPStatusError struct {
+	StatusCode int
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("response status code: %d, %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports whether target is a sentinel error matching the status code.
+func (e *HTTPStatusError) Is(target error) bool {
+	return statusIs(e.StatusCode, target)
+}
+
+func statusIs(code int, target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return code == http.StatusUnauthorized
+	case ErrForbidden:
+		return code == http.StatusForbidden
+	case ErrNotFound:
+		return code == http.StatusNotFound
+	case ErrClientStatus:
+		return code >= 400 && code < 500
+	case ErrServerStatus:
+		return code >= 500 && code < 600
+	}
+	return false
+}
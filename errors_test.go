@@ -0,0 +1,80 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusSentinels(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrClientStatus, ErrServerStatus}
+	tests := map[int][]error{
+		http.StatusUnauthorized:        {ErrUnauthorized, ErrClientStatus},
+		http.StatusForbidden:           {ErrForbidden, ErrClientStatus},
+		http.StatusNotFound:            {ErrNotFound, ErrClientStatus},
+		http.StatusTeapot:              {ErrClientStatus},
+		http.StatusInternalServerError: {ErrServerStatus},
+		http.StatusBadGateway:          {ErrServerStatus},
+	}
+	for code, want := range tests {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			for _, err := range []error{
+				errors.WithStack(&HTTPStatusError{StatusCode: code}),
+				errors.WithStack(&UnexpectedResponseError{StatusCode: code}),
+			} {
+				for _, target := range sentinels {
+					assert.Equal(t, contains(want, target), errors.Is(err, target), target.Error())
+				}
+			}
+		})
+	}
+}
+
+func contains(list []error, target error) bool {
+	for _, err := range list {
+		if err == target {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClient_Request_statusErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			w.WriteHeader(http.StatusForbidden)
+		case "/d":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL})
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = c.GetBasicStatus(ctx)
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+	assert.ErrorIs(t, err, ErrForbidden)
+	var statusErr *HTTPStatusError
+	assert.ErrorAs(t, err, &statusErr)
+
+	_, err = c.GetDeviceInfo(ctx)
+	assert.ErrorIs(t, err, ErrUnsupportedByFirmware)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = c.GetMeterReading(ctx)
+	assert.ErrorIs(t, err, ErrServerStatus)
+	assert.NotErrorIs(t, err, ErrClientStatus)
+}
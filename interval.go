@@ -7,7 +7,10 @@ package youless
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/go-pogo/errors"
 )
 
 type UnsupportedIntervalError struct {
@@ -19,6 +22,36 @@ func (e UnsupportedIntervalError) Error() string {
 	return fmt.Sprintf("utility %s does not support interval `%s`", e.Utility, e.Interval.String())
 }
 
+const ErrInvalidInterval errors.Msg = "invalid interval"
+
+// supports reports whether the log of Utility u is available in Interval i.
+// The device supports the following combinations:
+//
+//	            | PerMin | Per10min | PerHour | PerDay |
+//	Electricity |   x    |    x     |    x    |   x    |
+//	S0          |   x    |    x     |    x    |   x    |
+//	Gas         |        |    x     |    x    |   x    |
+//	Water       |        |    x     |    x    |   x    |
+//
+// Gas and water meters are not read often enough to be logged per minute.
+func supports(u Utility, i Interval) bool {
+	if !u.valid() || !i.valid() {
+		return false
+	}
+	return i != PerMin || (u != Gas && u != Water)
+}
+
+// withinHistory reports whether page is within the history the device keeps
+// for Interval i. For PerDay the device keeps the day values of the last 12
+// months, where each page is a month. The other intervals are not limited as
+// their history depth differs per model and firmware version, see MaxPages.
+func withinHistory(i Interval, page uint) bool {
+	if page <= 0 {
+		return false
+	}
+	return i != PerDay || page <= PerDay.MaxPages()
+}
+
 type Interval uint32
 
 const (
@@ -28,6 +61,27 @@ const (
 	PerDay   Interval = 86400
 )
 
+// ParseInterval parses s to a valid Interval. It accepts the String
+// representation of an Interval (e.g. "10min") or a duration string which
+// equals one of the intervals (e.g. "10m" or "1h"). It returns an
+// ErrInvalidInterval error when s is not a known Interval.
+func ParseInterval(s string) (Interval, error) {
+	str := strings.ToLower(strings.TrimSpace(s))
+	for _, i := range [...]Interval{PerMin, Per10min, PerHour, PerDay} {
+		if str == i.String() {
+			return i, nil
+		}
+	}
+	if d, err := time.ParseDuration(str); err == nil {
+		for _, i := range [...]Interval{PerMin, Per10min, PerHour, PerDay} {
+			if d == i.Duration() {
+				return i, nil
+			}
+		}
+	}
+	return 0, errors.Wrapf(ErrInvalidInterval, "parse %q", s)
+}
+
 func (i Interval) Delta() uint32 {
 	switch i {
 	case PerMin, Per10min, PerHour, PerDay:
@@ -67,6 +121,44 @@ func (i Interval) Param() rune {
 	}
 }
 
+// MaxPages returns the amount of log pages an LS120 is assumed to keep for
+// Interval. The values are not read from the device and may differ per
+// firmware version, an LS110 keeps less pages. For PerDay each page is a
+// month, for the other intervals page 1 contains the newest values and
+// MaxPages the oldest.
+func (i Interval) MaxPages() uint {
+	switch i {
+	case PerMin:
+		return 20
+	case Per10min:
+		return 30
+	case PerHour:
+		return 70
+	case PerDay:
+		return 12
+	default:
+		panic(invalidInterval(i))
+	}
+}
+
+// ParamE is similar to Param, except it returns an ErrInvalidInterval error
+// instead of panicking when Interval is not valid.
+func (i Interval) ParamE() (rune, error) {
+	if !i.valid() {
+		return 0, errors.Wrapf(ErrInvalidInterval, "%d", uint32(i))
+	}
+	return i.Param(), nil
+}
+
+func (i Interval) valid() bool {
+	switch i {
+	case PerMin, Per10min, PerHour, PerDay:
+		return true
+	default:
+		return false
+	}
+}
+
 // The String representation of Interval.
 func (i Interval) String() string {
 	switch i {
@@ -5,6 +5,8 @@
 package youless
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -80,3 +82,97 @@ func TestInterval_String(t *testing.T) {
 		})
 	})
 }
+
+func TestParseInterval(t *testing.T) {
+	tests := map[string]Interval{
+		"min":   PerMin,
+		"10min": Per10min,
+		"Hour":  PerHour,
+		"day":   PerDay,
+		"1m":    PerMin,
+		"10m":   Per10min,
+		"1h":    PerHour,
+		"24h":   PerDay,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have, err := ParseInterval(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	t.Run("invalid interval", func(t *testing.T) {
+		for _, input := range []string{"", "5", "5m", "week"} {
+			_, err := ParseInterval(input)
+			assert.ErrorIs(t, err, ErrInvalidInterval, input)
+		}
+	})
+}
+
+func TestInterval_ParamE(t *testing.T) {
+	have, err := PerHour.ParamE()
+	assert.NoError(t, err)
+	assert.Equal(t, 'd', have)
+
+	_, err = Interval(1).ParamE()
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+func TestSupports(t *testing.T) {
+	tests := map[Utility]map[Interval]bool{
+		Electricity: {PerMin: true, Per10min: true, PerHour: true, PerDay: true},
+		S0:          {PerMin: true, Per10min: true, PerHour: true, PerDay: true},
+		Gas:         {PerMin: false, Per10min: true, PerHour: true, PerDay: true},
+		Water:       {PerMin: false, Per10min: true, PerHour: true, PerDay: true},
+		"x":         {PerMin: false, Per10min: false, PerHour: false, PerDay: false},
+	}
+	for u, intervals := range tests {
+		for i, want := range intervals {
+			t.Run(string(u)+"/"+i.String(), func(t *testing.T) {
+				assert.Equal(t, want, supports(u, i))
+
+				if u == "x" {
+					return
+				}
+				api := &apiRequester{requesterFunc(func(context.Context, string, any) error {
+					return nil
+				})}
+				_, err := api.GetLog(context.Background(), u, i, 1)
+				if want {
+					assert.NoError(t, err)
+				} else {
+					var unsupported *UnsupportedIntervalError
+					assert.ErrorAs(t, err, &unsupported)
+				}
+			})
+		}
+	}
+	t.Run("invalid interval", func(t *testing.T) {
+		assert.False(t, supports(Electricity, 123))
+	})
+}
+
+func TestWithinHistory(t *testing.T) {
+	tests := map[Interval]map[uint]bool{
+		PerMin:   {0: false, 1: true, 20: true, 21: true},
+		Per10min: {0: false, 1: true, 30: true, 31: true},
+		PerHour:  {0: false, 1: true, 70: true, 71: true},
+		PerDay:   {0: false, 1: true, 12: true, 13: false},
+	}
+	for i, pages := range tests {
+		for page, want := range pages {
+			t.Run(fmt.Sprintf("%s/%d", i, page), func(t *testing.T) {
+				assert.Equal(t, want, withinHistory(i, page))
+			})
+		}
+	}
+}
+
+func TestInterval_MaxPages(t *testing.T) {
+	assert.Equal(t, uint(20), PerMin.MaxPages())
+	assert.Equal(t, uint(30), Per10min.MaxPages())
+	assert.Equal(t, uint(70), PerHour.MaxPages())
+	assert.Equal(t, uint(12), PerDay.MaxPages())
+	assert.Panics(t, func() { Interval(5).MaxPages() })
+}
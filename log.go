@@ -8,27 +8,56 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"strings"
 )
 
+// Logger logs the requests of a Client. A Logger may also implement a
+// LogUnknownAuthCookies(clientName string, cookies []*http.Cookie) method,
+// which is called when the device responded with cookies of which none has an
+// accepted auth cookie name.
 type Logger interface {
 	LogClientRequest(ctx context.Context, clientName, url string, shared bool)
 	LogFetchAuthCookie(clientName string, cookie http.Cookie)
 }
 
+// unknownAuthCookiesLogger is implemented by Loggers which log the cookies
+// of responses without an accepted auth cookie, e.g. the Logger returned by
+// DefaultLogger.
+type unknownAuthCookiesLogger interface {
+	LogUnknownAuthCookies(clientName string, cookies []*http.Cookie)
+}
+
 const panicNilLog = "youless.NewLogger: log.Logger should not be nil"
 
 func NewLogger(l *log.Logger) Logger {
 	if l == nil {
 		panic(panicNilLog)
 	}
-	return &defaultLogger{l}
+	return &defaultLogger{Logger: l}
 }
 
-func DefaultLogger() Logger { return &defaultLogger{log.Default()} }
+func DefaultLogger() Logger { return &defaultLogger{Logger: log.Default()} }
 
-type defaultLogger struct{ *log.Logger }
+type defaultLogger struct {
+	*log.Logger
+	// ctxKey is the key of the context value which is added to the log
+	// messages, see WithLogContextKey
+	ctxKey any
+}
 
-func (l *defaultLogger) LogClientRequest(_ context.Context, name, url string, shared bool) {
+func (l *defaultLogger) LogClientRequest(ctx context.Context, name, url string, shared bool) {
+	if l.ctxKey != nil && ctx != nil {
+		if v := ctx.Value(l.ctxKey); v != nil {
+			l.Logger.Printf("[%v] client %s requesting %s (shared: %t)\n", v, name, url, shared)
+			return
+		}
+	}
+	if ctx != nil {
+		if id, ok := RequestID(ctx); ok {
+			l.Logger.Printf("[%s] client %s requesting %s (shared: %t)\n", id, name, url, shared)
+			return
+		}
+	}
 	l.Logger.Printf("client %s requesting %s (shared: %t)\n", name, url, shared)
 }
 
@@ -36,6 +65,14 @@ func (l *defaultLogger) LogFetchAuthCookie(name string, cookie http.Cookie) {
 	l.Logger.Printf("client %s fetched auth cookie: %s\n", name, cookie.String())
 }
 
+func (l *defaultLogger) LogUnknownAuthCookies(name string, cookies []*http.Cookie) {
+	names := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		names = append(names, cookie.Name)
+	}
+	l.Logger.Printf("client %s received unknown auth cookies: %s\n", name, strings.Join(names, ", "))
+}
+
 func NopLogger() Logger { return new(nopLogger) }
 
 type nopLogger struct{}
@@ -0,0 +1,65 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type requestIDKey struct{}
+
+func TestWithLogContextKey(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	var buf strings.Builder
+	c, err := NewClient(Config{BaseURL: srv.URL, Name: "test"},
+		WithLogContextKey(requestIDKey{}),
+		WithLogger(NewLogger(log.New(&buf, "", 0))),
+	)
+	assert.NoError(t, err)
+
+	_, err = c.GetMeterReading(context.WithValue(context.Background(), requestIDKey{}, "abc"))
+	assert.NoError(t, err)
+	_, err = c.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "[abc] client test requesting "+srv.URL+"/e (shared: false)\n"+
+		"client test requesting "+srv.URL+"/e (shared: false)\n",
+		buf.String(),
+	)
+
+	t.Run("shared logger", func(t *testing.T) {
+		buf.Reset()
+		shared := NewLogger(log.New(&buf, "", 0))
+		c1, err := NewClient(Config{BaseURL: srv.URL, Name: "c1"}, WithLogger(shared))
+		assert.NoError(t, err)
+		c2, err := NewClient(Config{BaseURL: srv.URL, Name: "c2"},
+			WithLogger(shared),
+			WithLogContextKey(requestIDKey{}),
+		)
+		assert.NoError(t, err)
+
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "abc")
+		_, err = c1.GetMeterReading(ctx)
+		assert.NoError(t, err)
+		_, err = c2.GetMeterReading(ctx)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "client c1 requesting "+srv.URL+"/e (shared: false)\n"+
+			"[abc] client c2 requesting "+srv.URL+"/e (shared: false)\n",
+			buf.String(),
+		)
+	})
+}
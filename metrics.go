@@ -0,0 +1,71 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+//goland:noinspection GoUnusedConst
+const (
+	AttrPage = "youless.page"
+
+	MetricRequestDuration = "youless.client.request.duration"
+	MetricRequests        = "youless.client.requests"
+	MetricErrors          = "youless.client.errors"
+)
+
+type clientMetrics struct {
+	duration metric.Float64Histogram
+	requests metric.Int64Counter
+	errors   metric.Int64Counter
+}
+
+func newClientMetrics(m metric.Meter) (*clientMetrics, error) {
+	var cm clientMetrics
+	var err error
+
+	cm.duration, err = m.Float64Histogram(MetricRequestDuration,
+		metric.WithDescription("Duration of requests to the YouLess device."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cm.requests, err = m.Int64Counter(MetricRequests,
+		metric.WithDescription("Number of requests to the YouLess device."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	cm.errors, err = m.Int64Counter(MetricErrors,
+		metric.WithDescription("Number of failed requests to the YouLess device."),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &cm, nil
+}
+
+func (cm *clientMetrics) record(ctx context.Context, page string, dur time.Duration, err error, attrs ...attribute.KeyValue) {
+	// strip query to prevent high cardinality, e.g. with log page numbers
+	page, _, _ = strings.Cut(page, "?")
+	opt := metric.WithAttributes(append(attrs, attribute.String(AttrPage, page))...)
+	cm.duration.Record(ctx, dur.Seconds(), opt)
+	cm.requests.Add(ctx, 1, opt)
+	if err != nil {
+		cm.errors.Add(ctx, 1, opt)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestWithMeterProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/f" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	reader := sdkmetric.NewManualReader()
+	c, err := NewClient(Config{BaseURL: srv.URL},
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithDeviceInfo(DeviceInfoResponse{}),
+	)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = c.GetMeterReading(ctx)
+	assert.NoError(t, err)
+	_, err = c.GetPhaseReading(ctx)
+	assert.Error(t, err)
+
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(ctx, &rm))
+	assert.Len(t, rm.ScopeMetrics, 1)
+
+	have := make(map[string]int64)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch data := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			for _, dp := range data.DataPoints {
+				have[m.Name] += dp.Value
+			}
+		case metricdata.Histogram[float64]:
+			for _, dp := range data.DataPoints {
+				have[m.Name] += int64(dp.Count)
+			}
+		}
+	}
+	assert.Equal(t, map[string]int64{
+		MetricRequestDuration: 2,
+		MetricRequests:        2,
+		MetricErrors:          1,
+	}, have)
+}
+
+func TestClient_deviceAttrs(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/d" {
+			calls.Add(1)
+			_, _ = w.Write([]byte(`{"model":"LS120","fw":"1.6.1-EL","mac":"72:b8:ad:14:16:2e"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	want := []attribute.KeyValue{
+		attribute.String(AttrDeviceMAC, "72:b8:ad:14:16:2e"),
+		attribute.String(AttrDeviceModel, "LS120"),
+		attribute.String(AttrDeviceFirmware, "1.6.1-EL"),
+	}
+
+	t.Run("fetched once", func(t *testing.T) {
+		calls.Store(0)
+		c, err := NewClient(Config{BaseURL: srv.URL},
+			WithMeterProvider(sdkmetric.NewMeterProvider()),
+		)
+		assert.NoError(t, err)
+
+		assert.Equal(t, want, c.deviceAttrs(context.Background(), "e"))
+		assert.Equal(t, want, c.deviceAttrs(context.Background(), "f"))
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("preset", func(t *testing.T) {
+		calls.Store(0)
+		c, err := NewClient(Config{BaseURL: srv.URL},
+			WithMeterProvider(sdkmetric.NewMeterProvider()),
+			WithDeviceInfo(DeviceInfoResponse{
+				Model:    "LS120",
+				Firmware: "1.6.1-EL",
+				MAC:      "72:b8:ad:14:16:2e",
+			}),
+		)
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, want, c.deviceAttrs(context.Background(), "e"))
+		assert.Equal(t, int32(0), calls.Load())
+	})
+	t.Run("fetch failure", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: "http://127.0.0.1:1"},
+			WithMeterProvider(sdkmetric.NewMeterProvider()),
+		)
+		assert.NoError(t, err)
+		assert.Nil(t, c.deviceAttrs(context.Background(), "e"))
+		assert.Equal(t, deviceRetryMin, c.deviceBackoff)
+	})
+	t.Run("retry after failure", func(t *testing.T) {
+		var fail atomic.Bool
+		fail.Store(true)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if fail.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(`{"model":"LS120","fw":"1.6.1-EL","mac":"72:b8:ad:14:16:2e"}`))
+		}))
+		defer srv.Close()
+
+		c, err := NewClient(Config{BaseURL: srv.URL},
+			WithMeterProvider(sdkmetric.NewMeterProvider()),
+		)
+		assert.NoError(t, err)
+		assert.Nil(t, c.deviceAttrs(context.Background(), "e"))
+
+		// within the backoff the device info is not requested again
+		fail.Store(false)
+		assert.Nil(t, c.deviceAttrs(context.Background(), "e"))
+
+		c.deviceRetry = time.Time{}
+		assert.Equal(t, want, c.deviceAttrs(context.Background(), "e"))
+		assert.Equal(t, time.Duration(0), c.deviceBackoff)
+	})
+	t.Run("not instrumented", func(t *testing.T) {
+		calls.Store(0)
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+}
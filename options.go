@@ -5,23 +5,213 @@
 package youless
 
 import (
+	"crypto/tls"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-pogo/errors"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
-const ErrApplyOption errors.Msg = "failed to apply option"
+const (
+	ErrApplyOption   errors.Msg = "failed to apply option"
+	ErrClientCreated errors.Msg = "option must be applied when creating the client"
+)
 
 type Option func(c *Client) error
 
+// WithBaseURL sets Config.BaseURL. It returns an ErrInvalidBaseURL error when
+// the url is not valid.
+func WithBaseURL(url string) Option {
+	return func(c *Client) error {
+		if err := validateBaseURL(url); err != nil {
+			return err
+		}
+		c.Config.BaseURL = url
+		return nil
+	}
+}
+
+// WithName sets Config.Name.
+func WithName(name string) Option {
+	return func(c *Client) error {
+		c.Config.Name = name
+		return nil
+	}
+}
+
+// WithTimeout sets Config.Timeout. It returns an ErrInvalidTimeout error when
+// the timeout is negative.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return errors.New(ErrInvalidTimeout)
+		}
+		c.Config.Timeout = timeout
+		return nil
+	}
+}
+
+// WithPassword sets Config.Password.
+func WithPassword(password string) Option {
+	return func(c *Client) error {
+		c.Config.Password = password
+		return nil
+	}
+}
+
+// WithPasswordFile sets Config.PasswordFile.
+func WithPasswordFile(file string) Option {
+	return func(c *Client) error {
+		c.Config.PasswordFile = file
+		return nil
+	}
+}
+
 // WithHTTPClient sets the underlying http.Client for the client.
 func WithHTTPClient(client http.Client) Option {
 	return func(c *Client) error {
 		c.client = client
 		c.client.CheckRedirect = c.fetchAuthCookie(c.client.CheckRedirect)
+		c.transport = client.Transport
+		c.clientFunc = nil
+		return nil
+	}
+}
+
+// WithHTTPClientFunc sets a function which creates the underlying http.Client
+// when the Client sends its first request. The handling of the auth cookie
+// is applied to the created http.Client, as are the transport wrappers of
+// WithTracerProvider and WithDebugDump. Options which change the transport,
+// like WithTransport and WithTLSConfig, do not apply to it and should be
+// configured in fn instead.
+//
+// Multiple Clients can reuse the connections of a single *http.Transport by
+// returning an http.Client which uses it, while customizing other fields:
+//
+//	transport := &http.Transport{MaxIdleConnsPerHost: 2}
+//	WithHTTPClientFunc(func() http.Client {
+//		return http.Client{Transport: transport, Timeout: 10 * time.Second}
+//	})
+//
+// The transport is not owned by the Client, Close does not close its idle
+// connections. WithHTTPClientFunc can only be used with NewClient, applying
+// it using Client.With returns an ErrClientCreated error.
+func WithHTTPClientFunc(fn func() http.Client) Option {
+	return func(c *Client) error {
+		if c.created {
+			return errors.New(ErrClientCreated)
+		}
+		c.clientFunc = fn
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper of the underlying http.Client,
+// without replacing the http.Client itself. The handling of the auth cookie is
+// preserved. When WithTracerProvider is used, either before or after
+// WithTransport, rt is wrapped with an otelhttp.Transport to trace all requests.
+// The same applies to the dumping of requests enabled with WithDebugDump.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) error {
+		c.transport = rt
+		if c.wrapTransport != nil {
+			rt = c.wrapTransport(rt)
+		}
+		c.client.Transport = rt
+		return nil
+	}
+}
+
+const ErrTLSConfigTransport errors.Msg = "cannot set tls config on transport"
+
+// WithTLSConfig sets the tls.Config of the underlying http.Client's
+// transport, e.g. to trust the self-signed certificate of an HTTPS reverse
+// proxy in front of the device. It applies to a clone of the transport set
+// with WithHTTPClient or WithTransport, which must be an *http.Transport, or
+// http.DefaultTransport when none is set. The handling of the auth cookie and
+// tracing of requests is preserved, see WithTransport.
+//
+// Setting tls.Config.InsecureSkipVerify disables the verification of the
+// server's certificate chain and host name. This makes the connection
+// vulnerable to man-in-the-middle attacks, which could expose the device's
+// password and auth cookie. Prefer adding the proxy's certificate to
+// tls.Config.RootCAs instead.
+func WithTLSConfig(conf *tls.Config) Option {
+	return func(c *Client) error {
+		base := c.transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+
+		t, ok := base.(*http.Transport)
+		if !ok {
+			return errors.Wrapf(ErrTLSConfigTransport, "unsupported type %T", base)
+		}
+
+		t = t.Clone()
+		t.TLSClientConfig = conf
+		return WithTransport(t)(c)
+	}
+}
+
+// WithStopOnAuthRedirect stops following redirects of a request once the
+// auth cookie is captured from a redirect response, the redirect response
+// itself is then the result of the request. By default, the redirect is
+// followed with the captured cookie. Redirects of Authorize are never
+// followed, as only the cookie is needed.
+func WithStopOnAuthRedirect() Option {
+	return func(c *Client) error {
+		c.stopOnAuth = true
+		return nil
+	}
+}
+
+// WithManualAuth disables the automatic authorization of the Client. The auth
+// cookie is then only captured from the redirect following an explicit call
+// to Authorize, redirects of all other requests are followed as usual. This
+// is useful when the device is behind a proxy which redirects requests.
+// Config.Password and Config.PasswordFile are not used to authorize.
+func WithManualAuth() Option {
+	return func(c *Client) error {
+		c.manualAuth = true
+		return nil
+	}
+}
+
+// WithBasicAuth sets the credentials for HTTP Basic authentication, which are
+// sent with every request. This is useful when the device is behind a reverse
+// proxy which requires Basic authentication. Basic authentication and the
+// device's own password/auth cookie flow are mutually exclusive; when set,
+// Config.Password and Config.PasswordFile are ignored and no auth cookie is
+// requested.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) error {
+		c.basicAuth = &basicAuth{username, password}
+		return nil
+	}
+}
+
+// WithCookieStore sets the CookieStore which is used to load and save the auth
+// cookie, so it can be reused across process restarts.
+func WithCookieStore(s CookieStore) Option {
+	return func(c *Client) error {
+		c.store = s
+		return nil
+	}
+}
+
+// WithGroupWindow sets the duration the result of a request is reused for
+// identical requests made after it has completed. By default, only concurrent
+// identical requests are grouped into a single request to the device.
+func WithGroupWindow(d time.Duration) Option {
+	return func(c *Client) error {
+		c.groupWindow = d
 		return nil
 	}
 }
@@ -46,16 +236,19 @@ const TracerName string = "youless-client"
 
 // WithTracerProvider sets a new tracer for the client from the specified
 // tracer provider.
+// The client's http.RoundTripper is wrapped with an otelhttp.Transport, also
+// when it is replaced afterwards using WithTransport.
 func WithTracerProvider(tp trace.TracerProvider) Option {
 	return func(c *Client) error {
 		c.tracer = tp.Tracer(TracerName)
-		c.client.Transport = otelhttp.NewTransport(
-			c.client.Transport,
-			otelhttp.WithTracerProvider(tp),
-			otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
-				return req.Method + " " + req.URL.Path
-			}),
-		)
+		c.addTransportWrapper(func(rt http.RoundTripper) http.RoundTripper {
+			return otelhttp.NewTransport(rt,
+				otelhttp.WithTracerProvider(tp),
+				otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+					return req.Method + " " + req.URL.Path
+				}),
+			)
+		})
 		return nil
 	}
 }
@@ -63,3 +256,211 @@ func WithTracerProvider(tp trace.TracerProvider) Option {
 func WithDefaultTracerProvider() Option {
 	return WithTracerProvider(otel.GetTracerProvider())
 }
+
+// WithMeterProvider sets a new meter for the client from the specified meter
+// provider. The meter is used to record the duration, count and errors of the
+// requests made to the device.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *Client) error {
+		m, err := newClientMetrics(mp.Meter(TracerName))
+		if err != nil {
+			return err
+		}
+		c.metrics = m
+		return nil
+	}
+}
+
+func WithDefaultMeterProvider() Option {
+	return WithMeterProvider(otel.GetMeterProvider())
+}
+
+// WithDeviceInfo sets the DeviceInfoResponse which is used to add device
+// attributes to spans and metrics. This prevents the Client from requesting it
+// from the device when using WithTracerProvider or WithMeterProvider.
+func WithDeviceInfo(info DeviceInfoResponse) Option {
+	return func(c *Client) error {
+		c.device.Store(&info)
+		return nil
+	}
+}
+
+// WithDeviceInfoCache caches the response of GetDeviceInfo for ttl. Calls
+// within this window return the cached DeviceInfoResponse without sending a
+// request to the device. The cache is cleared by Close.
+func WithDeviceInfoCache(ttl time.Duration) Option {
+	return func(c *Client) error {
+		c.infoCache.ttl = ttl
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header which is sent with all requests to
+// the device. By default, DefaultUserAgent is used.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) error {
+		c.userAgent = ua
+		return nil
+	}
+}
+
+// WithAuthCookieName sets the accepted name(s) of the auth cookie the device
+// responds with after authorizing. By default, DefaultAuthCookieName is used.
+func WithAuthCookieName(names ...string) Option {
+	return func(c *Client) error {
+		c.authCookieNames = names
+		return nil
+	}
+}
+
+// WithLocation sets the location of the device's local time. It is used to
+// interpret the timestamps of gas and water readings and logs, which the
+// device reports in its local time without timezone information, and by
+// Client.ParseTimestamp. By default, time.Local is used.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) error {
+		c.loc = loc
+		return nil
+	}
+}
+
+// WithTelegramFallback makes GetMeterReading derive the MeterReadingResponse
+// from the parsed P1 telegram, see MeterReadingFromTelegram, when the device's
+// firmware does not support the meter reading page.
+func WithTelegramFallback() Option {
+	return func(c *Client) error {
+		c.telegramFallback = true
+		return nil
+	}
+}
+
+// WithBasicStatusFallback makes GetMeterReading derive a minimal
+// MeterReadingResponse from the basic status, see MeterReadingFromBasicStatus,
+// when the device's firmware does not support the meter reading page. This
+// allows using legacy devices, e.g. a LS110 with non-enologic firmware. When
+// WithTelegramFallback is also used, the P1 telegram is tried first.
+func WithBasicStatusFallback() Option {
+	return func(c *Client) error {
+		c.basicStatusFallback = true
+		return nil
+	}
+}
+
+// WithTelegramMaxPages sets the maximum number of pages GetP1Telegram
+// requests before giving up on finding the end of the telegram. By default,
+// DefaultTelegramMaxPages is used.
+func WithTelegramMaxPages(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New(ErrInvalidTelegramMaxPages)
+		}
+		c.telegramMaxPages = n
+		return nil
+	}
+}
+
+// WithBatchConcurrency sets the maximum number of requests GetLogsBatch
+// executes concurrently. By default, DefaultBatchConcurrency is used.
+func WithBatchConcurrency(n int) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New(ErrInvalidBatchConcurrency)
+		}
+		c.batchConcurrency = n
+		return nil
+	}
+}
+
+// WithMaxResponseSize sets the maximum size in bytes of a response body read
+// by Request. Larger bodies result in an ErrResponseTooLarge error. By
+// default, DefaultMaxResponseSize is used.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) error {
+		if n <= 0 {
+			return errors.New(ErrInvalidMaxResponseSize)
+		}
+		c.maxResponseSize = n
+		return nil
+	}
+}
+
+// WithDecimalPrecision rounds the float64 fields of the meter reading, phase
+// reading and basic status responses to n decimals when they are decoded.
+// This removes noise like 1234.5670000001 caused by float imprecision. N must
+// be between 0 and MaxDecimalPrecision. By default, no rounding is applied.
+func WithDecimalPrecision(n int) Option {
+	return func(c *Client) error {
+		if n < 0 || n > MaxDecimalPrecision {
+			return errors.New(ErrInvalidDecimalPrecision)
+		}
+		c.decimals = n
+		c.round = true
+		return nil
+	}
+}
+
+// WithDebugDump writes a dump of each request sent to, and response received
+// from, the device to w. The password sent by Authorize, the Cookie and
+// Authorization request headers and the Set-Cookie response headers are
+// redacted, so the output can safely be shared in a bug report. Like
+// WithTracerProvider, it also applies to a transport set afterwards using
+// WithTransport.
+func WithDebugDump(w io.Writer) Option {
+	return func(c *Client) error {
+		c.addTransportWrapper(func(rt http.RoundTripper) http.RoundTripper {
+			return &dumpTransport{rt: rt, w: w, limit: c.MaxResponseSize}
+		})
+		return nil
+	}
+}
+
+// WithRequestIDGenerator generates a request id using gen for each request
+// whose context does not carry one, see WithRequestID. NewRequestID is used
+// when gen is nil. By default, no request ids are generated.
+func WithRequestIDGenerator(gen func() string) Option {
+	return func(c *Client) error {
+		if gen == nil {
+			gen = NewRequestID
+		}
+		c.requestIDGen = gen
+		return nil
+	}
+}
+
+// WithRequestHook adds hooks which are called before and after each request
+// is sent to the device. The before hook may modify the request, e.g. to add
+// a header. The after hook receives the response, or error, and must not read
+// or close the response's body. Either hook may be nil.
+func WithRequestHook(before func(req *http.Request), after func(res *http.Response, err error)) Option {
+	return func(c *Client) error {
+		if before != nil {
+			c.beforeHooks = append(c.beforeHooks, before)
+		}
+		if after != nil {
+			c.afterHooks = append(c.afterHooks, after)
+		}
+		return nil
+	}
+}
+
+// WithRateLimit limits the rate of requests to the device to rps requests per
+// second, with bursts of at most burst requests. The limit is shared by all
+// requests of the Client, requests block until they are allowed or their
+// context is done. By default, the rate of requests is unlimited.
+func WithRateLimit(rps float64, burst int) Option {
+	return func(c *Client) error {
+		c.limiter = rate.NewLimiter(rate.Limit(rps), burst)
+		return nil
+	}
+}
+
+// WithLogContextKey sets the key of a context value, e.g. a request id, which
+// is added to the messages logged by the Logger returned by DefaultLogger or
+// NewLogger. Messages of requests without the context value are logged as
+// usual.
+func WithLogContextKey(key any) Option {
+	return func(c *Client) error {
+		c.logCtxKey = key
+		return nil
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestNewClient_configOptions(t *testing.T) {
+	t.Run("options take precedence", func(t *testing.T) {
+		c, err := NewClient(
+			Config{BaseURL: "http://youless", Name: "conf", Timeout: time.Second},
+			WithBaseURL("http://192.168.1.10"),
+			WithName("opt"),
+			WithTimeout(2*time.Second),
+			WithPassword("pass"),
+			WithPasswordFile("/run/secrets/youless"),
+		)
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			BaseURL:      "http://192.168.1.10",
+			Name:         "opt",
+			Timeout:      2 * time.Second,
+			Password:     "pass",
+			PasswordFile: "/run/secrets/youless",
+		}, c.Config)
+	})
+	t.Run("invalid base url", func(t *testing.T) {
+		_, err := NewClient(Config{}, WithBaseURL("htp://youless"))
+		assert.ErrorIs(t, err, ErrApplyOption)
+		assert.ErrorIs(t, err, ErrInvalidBaseURL)
+	})
+	t.Run("negative timeout", func(t *testing.T) {
+		_, err := NewClient(Config{}, WithTimeout(-time.Second))
+		assert.ErrorIs(t, err, ErrApplyOption)
+		assert.ErrorIs(t, err, ErrInvalidTimeout)
+	})
+}
+
+type countingTransport struct{ calls atomic.Int32 }
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: "tk", Value: "secret"})
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	tracing := WithTracerProvider(noop.NewTracerProvider())
+	tests := map[string]func(rt http.RoundTripper) []Option{
+		"without tracing": func(rt http.RoundTripper) []Option {
+			return []Option{WithTransport(rt)}
+		},
+		"tracing before": func(rt http.RoundTripper) []Option {
+			return []Option{tracing, WithTransport(rt)}
+		},
+		"tracing after": func(rt http.RoundTripper) []Option {
+			return []Option{WithTransport(rt), tracing}
+		},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			var rt countingTransport
+			c, err := NewClient(Config{BaseURL: srv.URL, Password: "secret"},
+				append(opts(&rt), WithDeviceInfo(DeviceInfoResponse{}))...,
+			)
+			assert.NoError(t, err)
+			if c.tracer != nil {
+				assert.IsType(t, &otelhttp.Transport{}, c.client.Transport)
+			}
+
+			_, err = c.GetMeterReading(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", c.cookie.Load().Value)
+			assert.Equal(t, int32(2), rt.calls.Load())
+		})
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: "tk", Value: "secret"})
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	conf := &tls.Config{RootCAs: pool}
+
+	t.Run("untrusted", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.Error(t, err)
+	})
+
+	tracing := WithTracerProvider(noop.NewTracerProvider())
+	tests := map[string][]Option{
+		"without tracing": {WithTLSConfig(conf)},
+		"tracing before":  {tracing, WithTLSConfig(conf)},
+		"tracing after":   {WithTLSConfig(conf), tracing},
+		"custom transport": {
+			WithTransport(&http.Transport{MaxIdleConns: 1}),
+			WithTLSConfig(conf),
+		},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := NewClient(Config{BaseURL: srv.URL, Password: "secret"},
+				append(opts, WithDeviceInfo(DeviceInfoResponse{}))...,
+			)
+			assert.NoError(t, err)
+			if c.tracer != nil {
+				assert.IsType(t, &otelhttp.Transport{}, c.client.Transport)
+			}
+
+			_, err = c.GetMeterReading(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", c.cookie.Load().Value)
+		})
+	}
+
+	t.Run("unsupported transport", func(t *testing.T) {
+		_, err := NewClient(Config{BaseURL: srv.URL},
+			WithTransport(&countingTransport{}),
+			WithTLSConfig(conf),
+		)
+		assert.ErrorIs(t, err, ErrTLSConfigTransport)
+	})
+}
+
+func TestWithHTTPClientFunc(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			http.SetCookie(w, &http.Cookie{Name: DefaultAuthCookieName, Value: "secret"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+			return
+		}
+		if c, err := r.Cookie(DefaultAuthCookieName); err != nil || c.Value != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	var created, dumped atomic.Int32
+	transport := &http.Transport{}
+	newClient := func() http.Client {
+		created.Add(1)
+		return http.Client{Transport: transport, Timeout: time.Second}
+	}
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Password: "password"},
+		WithHTTPClientFunc(newClient),
+		func(c *Client) error {
+			c.addTransportWrapper(func(rt http.RoundTripper) http.RoundTripper {
+				return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					dumped.Add(1)
+					return rt.RoundTrip(req)
+				})
+			})
+			return nil
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), created.Load(), "must be created lazily")
+
+	for i := 0; i < 2; i++ {
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+	}
+	assert.Equal(t, int32(1), created.Load())
+	assert.Equal(t, transport, c.transport)
+	assert.Equal(t, int32(3), dumped.Load(), "auth and two requests")
+
+	assert.ErrorIs(t, c.With(WithHTTPClientFunc(newClient)), ErrClientCreated)
+}
+
+type idleCloser struct {
+	http.RoundTripper
+	closed atomic.Bool
+}
+
+func (ic *idleCloser) CloseIdleConnections() { ic.closed.Store(true) }
+
+func TestWithHTTPClientFunc_close(t *testing.T) {
+	shared := &idleCloser{RoundTripper: http.DefaultTransport}
+	c, err := NewClient(Config{BaseURL: "http://127.0.0.1:1"},
+		WithHTTPClientFunc(func() http.Client {
+			return http.Client{Transport: shared}
+		}),
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+	assert.False(t, shared.closed.Load(), "shared transport must not be closed")
+
+	owned := &idleCloser{RoundTripper: http.DefaultTransport}
+	c, err = NewClient(Config{BaseURL: "http://127.0.0.1:1"}, WithTransport(owned))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+	assert.True(t, owned.closed.Load())
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"math"
+
+	"github.com/go-pogo/errors"
+)
+
+// MaxDecimalPrecision is the maximum number of decimals accepted by
+// WithDecimalPrecision. A float64 has at most 15 to 17 significant digits,
+// larger precisions would overflow the rounding.
+const MaxDecimalPrecision = 15
+
+const ErrInvalidDecimalPrecision errors.Msg = "decimal precision must be between 0 and 15"
+
+// precisionRounder is implemented by responses which contain float64 fields
+// that are rounded when the Client is created with WithDecimalPrecision.
+type precisionRounder interface {
+	roundFloats(decimals int)
+}
+
+// roundTo rounds f to the given number of decimals.
+func roundTo(f float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(f*p) / p
+}
+
+// roundResponse rounds the float64 fields of out, when it is a response which
+// contains them.
+func roundResponse(out any, decimals int) {
+	switch o := out.(type) {
+	case precisionRounder:
+		o.roundFloats(decimals)
+	case *[]MeterReadingResponse:
+		for i := range *o {
+			(*o)[i].roundFloats(decimals)
+		}
+	}
+}
+
+func (r *MeterReadingResponse) roundFloats(decimals int) {
+	r.ElectricityImport1 = roundTo(r.ElectricityImport1, decimals)
+	r.ElectricityImport2 = roundTo(r.ElectricityImport2, decimals)
+	r.ElectricityExport1 = roundTo(r.ElectricityExport1, decimals)
+	r.ElectricityExport2 = roundTo(r.ElectricityExport2, decimals)
+	r.NetElectricity = roundTo(r.NetElectricity, decimals)
+	r.S0Total = roundTo(r.S0Total, decimals)
+	r.GasTotal = roundTo(r.GasTotal, decimals)
+	r.WaterTotal = roundTo(r.WaterTotal, decimals)
+	if r.secondaryS0 != nil {
+		r.secondaryS0.S0Total = roundTo(r.secondaryS0.S0Total, decimals)
+	}
+}
+
+func (r *PhaseReadingResponse) roundFloats(decimals int) {
+	r.Current1 = roundTo(r.Current1, decimals)
+	r.Current2 = roundTo(r.Current2, decimals)
+	r.Current3 = roundTo(r.Current3, decimals)
+	r.Voltage1 = roundTo(r.Voltage1, decimals)
+	r.Voltage2 = roundTo(r.Voltage2, decimals)
+	r.Voltage3 = roundTo(r.Voltage3, decimals)
+}
+
+func (r *BasicStatusResponse) roundFloats(decimals int) {
+	r.Count = roundTo(r.Count, decimals)
+}
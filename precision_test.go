@@ -0,0 +1,60 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTo(t *testing.T) {
+	assert.Equal(t, 1234.567, roundTo(1234.5670000001, 3))
+	assert.Equal(t, 1234.57, roundTo(1234.5670000001, 2))
+	assert.Equal(t, 1235.0, roundTo(1234.5670000001, 0))
+}
+
+func TestWithDecimalPrecision(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"p1":1234.5670000001,"gas":456.78900000002,"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	t.Run("default", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1234.5670000001, have.ElectricityImport1)
+	})
+	t.Run("rounded", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithDecimalPrecision(3))
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 1234.567, have.ElectricityImport1)
+		assert.Equal(t, 456.789, have.GasTotal)
+		assert.Equal(t, int64(350), have.Power)
+	})
+	t.Run("max", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithDecimalPrecision(MaxDecimalPrecision))
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.InDelta(t, 1234.5670000001, have.ElectricityImport1, 1e-9)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, n := range []int{-1, MaxDecimalPrecision + 1, 400} {
+			_, err := NewClient(Config{BaseURL: srv.URL}, WithDecimalPrecision(n))
+			assert.ErrorIs(t, err, ErrInvalidDecimalPrecision, n)
+		}
+	})
+}
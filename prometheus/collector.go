@@ -0,0 +1,152 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package prometheus contains a prometheus.Collector which exposes the meter
+// and phase readings of a YouLess device as Prometheus metrics.
+package prometheus
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/roeldev/youless-client"
+)
+
+const namespace = "youless"
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// Collector is a prometheus.Collector which requests the meter and phase
+// readings from a YouLess device on each scrape.
+type Collector struct {
+	api youless.API
+
+	power       *prometheus.Desc
+	imported    *prometheus.Desc
+	exported    *prometheus.Desc
+	s0Power     *prometheus.Desc
+	s0Total     *prometheus.Desc
+	gasTotal    *prometheus.Desc
+	waterTotal  *prometheus.Desc
+	phaseVolt   *prometheus.Desc
+	phaseAmp    *prometheus.Desc
+	phasePower  *prometheus.Desc
+	scrapeError *prometheus.CounterVec
+}
+
+// NewCollector returns a new Collector which uses api to request the readings
+// from the YouLess device. A youless.Client can be used as api.
+func NewCollector(api youless.API) *Collector {
+	tariff := []string{"tariff"}
+	phase := []string{"phase"}
+
+	return &Collector{
+		api: api,
+		power: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "electricity", "power_watts"),
+			"Current imported (or negative for exported) electricity power in Watt.",
+			nil, nil,
+		),
+		imported: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "electricity", "import_kwh_total"),
+			"Meter reading of total imported electricity in kWh.",
+			tariff, nil,
+		),
+		exported: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "electricity", "export_kwh_total"),
+			"Meter reading of total exported electricity in kWh.",
+			tariff, nil,
+		),
+		s0Power: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "s0", "power_watts"),
+			"Current electricity power in Watt measured by the S0 meter.",
+			nil, nil,
+		),
+		s0Total: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "s0", "kwh_total"),
+			"Total electricity in kWh measured by the S0 meter.",
+			nil, nil,
+		),
+		gasTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "gas", "m3_total"),
+			"Meter reading of delivered gas in m3.",
+			nil, nil,
+		),
+		waterTotal: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "water", "m3_total"),
+			"Meter reading of delivered water in m3.",
+			nil, nil,
+		),
+		phaseVolt: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "phase", "voltage_volts"),
+			"Current measured voltage per phase.",
+			phase, nil,
+		),
+		phaseAmp: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "phase", "current_amperes"),
+			"Current imported electricity current in Ampere per phase.",
+			phase, nil,
+		),
+		phasePower: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "phase", "power_watts"),
+			"Current imported electricity power in Watt per phase.",
+			phase, nil,
+		),
+		scrapeError: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_errors_total",
+			Help:      "Total number of failed requests to the YouLess device while scraping.",
+		}, []string{"call"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.power
+	ch <- c.imported
+	ch <- c.exported
+	ch <- c.s0Power
+	ch <- c.s0Total
+	ch <- c.gasTotal
+	ch <- c.waterTotal
+	ch <- c.phaseVolt
+	ch <- c.phaseAmp
+	ch <- c.phasePower
+	c.scrapeError.Describe(ch)
+}
+
+// Collect implements prometheus.Collector. It requests the meter and phase
+// readings from the device. A failed request increments the scrape errors
+// counter, the metrics of that reading are then omitted.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx := context.Background()
+
+	if r, err := c.api.GetMeterReading(ctx); err != nil {
+		c.scrapeError.WithLabelValues("GetMeterReading").Inc()
+	} else {
+		ch <- prometheus.MustNewConstMetric(c.power, prometheus.GaugeValue, float64(r.Power))
+		ch <- prometheus.MustNewConstMetric(c.imported, prometheus.CounterValue, r.ElectricityImport1, "1")
+		ch <- prometheus.MustNewConstMetric(c.imported, prometheus.CounterValue, r.ElectricityImport2, "2")
+		ch <- prometheus.MustNewConstMetric(c.exported, prometheus.CounterValue, r.ElectricityExport1, "1")
+		ch <- prometheus.MustNewConstMetric(c.exported, prometheus.CounterValue, r.ElectricityExport2, "2")
+		ch <- prometheus.MustNewConstMetric(c.s0Power, prometheus.GaugeValue, float64(r.S0))
+		ch <- prometheus.MustNewConstMetric(c.s0Total, prometheus.CounterValue, r.S0Total)
+		ch <- prometheus.MustNewConstMetric(c.gasTotal, prometheus.CounterValue, r.GasTotal)
+		ch <- prometheus.MustNewConstMetric(c.waterTotal, prometheus.CounterValue, r.WaterTotal)
+	}
+
+	if r, err := c.api.GetPhaseReading(ctx); err != nil {
+		c.scrapeError.WithLabelValues("GetPhaseReading").Inc()
+	} else {
+		for i, p := range []youless.PhaseReading{r.Phase1(), r.Phase2(), r.Phase3()} {
+			phase := strconv.Itoa(i + 1)
+			ch <- prometheus.MustNewConstMetric(c.phaseVolt, prometheus.GaugeValue, p.Voltage, phase)
+			ch <- prometheus.MustNewConstMetric(c.phaseAmp, prometheus.GaugeValue, p.Current, phase)
+			ch <- prometheus.MustNewConstMetric(c.phasePower, prometheus.GaugeValue, float64(p.Power), phase)
+		}
+	}
+
+	c.scrapeError.Collect(ch)
+}
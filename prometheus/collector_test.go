@@ -0,0 +1,50 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package prometheus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/roeldev/youless-client"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubAPI struct {
+	youless.API
+	err error
+}
+
+func (s *stubAPI) GetMeterReading(context.Context) (youless.MeterReadingResponse, error) {
+	var res youless.MeterReadingResponse
+	res.Power = 350
+	res.ElectricityImport1 = 1234.5
+	return res, s.err
+}
+
+func (s *stubAPI) GetPhaseReading(context.Context) (youless.PhaseReadingResponse, error) {
+	return youless.PhaseReadingResponse{Voltage1: 230.1}, s.err
+}
+
+func TestCollector(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		c := NewCollector(&stubAPI{})
+		reg := prometheus.NewPedanticRegistry()
+		assert.NoError(t, reg.Register(c))
+
+		// 9 meter metrics + 3x3 phase metrics
+		assert.Equal(t, 18, testutil.CollectAndCount(c))
+		assert.Equal(t, float64(0), testutil.ToFloat64(c.scrapeError.WithLabelValues("GetMeterReading")))
+	})
+	t.Run("error", func(t *testing.T) {
+		c := NewCollector(&stubAPI{err: errors.New("some error")})
+		assert.Equal(t, 2, testutil.CollectAndCount(c))
+		assert.Equal(t, float64(1), testutil.ToFloat64(c.scrapeError.WithLabelValues("GetMeterReading")))
+		assert.Equal(t, float64(1), testutil.ToFloat64(c.scrapeError.WithLabelValues("GetPhaseReading")))
+	})
+}
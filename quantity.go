@@ -0,0 +1,53 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"strconv"
+	"time"
+)
+
+// Power is an amount of electrical power in Watt.
+type Power int64
+
+// String returns the Power formatted with its unit, e.g. "350 W".
+func (p Power) String() string { return strconv.FormatInt(int64(p), 10) + " W" }
+
+// KW returns the Power in kW.
+func (p Power) KW() float64 { return float64(p) / 1000 }
+
+// Over returns the Energy consumed when the Power is sustained for d.
+func (p Power) Over(d time.Duration) Energy {
+	return Energy(float64(p) * d.Hours() / 1000)
+}
+
+// Energy is an amount of electrical energy in kWh.
+type Energy float64
+
+// String returns the Energy formatted with its unit, e.g. "1234.567 kWh".
+func (e Energy) String() string {
+	return strconv.FormatFloat(float64(e), 'f', -1, 64) + " kWh"
+}
+
+// Wh returns the Energy in Wh.
+func (e Energy) Wh() float64 { return float64(e) * 1000 }
+
+// PowerValue returns Power as a typed Power value.
+func (r ElectricityReading) PowerValue() Power { return Power(r.Power) }
+
+// ImportEnergy returns the total imported electricity of both tariffs
+// (ElectricityImport1 + ElectricityImport2).
+func (r ElectricityReading) ImportEnergy() Energy {
+	return Energy(r.ElectricityImport1 + r.ElectricityImport2)
+}
+
+// ExportEnergy returns the total exported electricity of both tariffs
+// (ElectricityExport1 + ElectricityExport2).
+func (r ElectricityReading) ExportEnergy() Energy {
+	return Energy(r.ElectricityExport1 + r.ElectricityExport2)
+}
+
+// NetEnergy returns NetElectricity as a typed Energy value.
+func (r ElectricityReading) NetEnergy() Energy { return Energy(r.NetElectricity) }
@@ -0,0 +1,41 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPower(t *testing.T) {
+	p := Power(1500)
+	assert.Equal(t, "1500 W", p.String())
+	assert.Equal(t, 1.5, p.KW())
+	assert.InDelta(t, 0.75, float64(p.Over(30*time.Minute)), 1e-9)
+	assert.Equal(t, "-120 W", Power(-120).String())
+}
+
+func TestEnergy(t *testing.T) {
+	e := Energy(1234.567)
+	assert.Equal(t, "1234.567 kWh", e.String())
+	assert.InDelta(t, 1234567, e.Wh(), 1e-6)
+}
+
+func TestElectricityReading_typed(t *testing.T) {
+	r := ElectricityReading{
+		ElectricityImport1: 1000.123,
+		ElectricityImport2: 1200.456,
+		ElectricityExport1: 400.001,
+		ElectricityExport2: 566.011,
+		NetElectricity:     1234.567,
+		Power:              350,
+	}
+	assert.Equal(t, Power(350), r.PowerValue())
+	assert.InDelta(t, 2200.579, float64(r.ImportEnergy()), 1e-9)
+	assert.InDelta(t, 966.012, float64(r.ExportEnergy()), 1e-9)
+	assert.Equal(t, Energy(1234.567), r.NetEnergy())
+}
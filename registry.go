@@ -0,0 +1,134 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrDuplicateClient errors.Msg = "client with name already registered"
+	ErrMissingName     errors.Msg = "client name is empty"
+)
+
+// Registry holds multiple named Clients, e.g. to monitor several YouLess
+// devices. Its zero value is ready to use, Registry is safe for concurrent
+// use.
+type Registry struct {
+	mut     sync.RWMutex
+	clients map[string]*Client
+}
+
+// NewRegistry returns a Registry with the provided Clients added using their
+// Config.Name.
+func NewRegistry(clients ...*Client) (*Registry, error) {
+	var r Registry
+	var err error
+	for _, c := range clients {
+		errors.AppendInto(&err, r.Add("", c))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Add adds Client c with name to the Registry. When name is empty, the
+// Client's Config.Name is used. It returns an ErrDuplicateClient error when
+// a Client with the same name is already added.
+func (r *Registry) Add(name string, c *Client) error {
+	if name == "" {
+		name = c.Config.Name
+	}
+	if name == "" {
+		return errors.New(ErrMissingName)
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if _, ok := r.clients[name]; ok {
+		return errors.Wrapf(ErrDuplicateClient, "name %q", name)
+	}
+	if r.clients == nil {
+		r.clients = make(map[string]*Client, 2)
+	}
+	r.clients[name] = c
+	return nil
+}
+
+// Get returns the Client with name, it reports false when there is no such
+// Client.
+func (r *Registry) Get(name string) (*Client, bool) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	c, ok := r.clients[name]
+	return c, ok
+}
+
+// Remove removes the Client with name from the Registry. The Client itself is
+// not closed.
+func (r *Registry) Remove(name string) {
+	r.mut.Lock()
+	delete(r.clients, name)
+	r.mut.Unlock()
+}
+
+// Names returns the sorted names of all Clients in the Registry.
+func (r *Registry) Names() []string {
+	r.mut.RLock()
+	res := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		res = append(res, name)
+	}
+	r.mut.RUnlock()
+
+	sort.Strings(res)
+	return res
+}
+
+// SnapshotAll concurrently requests the meter reading of all Clients in the
+// Registry, keyed by their name. When any of the requests fail, the readings
+// of the successful requests are still returned together with the errors of
+// the failed ones, each wrapped with the name of its Client.
+func (r *Registry) SnapshotAll(ctx context.Context) (map[string]MeterReadingResponse, error) {
+	r.mut.RLock()
+	clients := make(map[string]*Client, len(r.clients))
+	for name, c := range r.clients {
+		clients[name] = c
+	}
+	r.mut.RUnlock()
+
+	var (
+		mut sync.Mutex
+		wg  sync.WaitGroup
+		err error
+	)
+
+	res := make(map[string]MeterReadingResponse, len(clients))
+	wg.Add(len(clients))
+	for name, c := range clients {
+		go func(name string, c *Client) {
+			defer wg.Done()
+			reading, readErr := c.GetMeterReading(ctx)
+
+			mut.Lock()
+			defer mut.Unlock()
+			if readErr != nil {
+				errors.AppendInto(&err, errors.Wrapf(readErr, "client %q", name))
+				return
+			}
+			res[name] = reading
+		}(name, c)
+	}
+	wg.Wait()
+
+	return res, err
+}
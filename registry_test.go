@@ -0,0 +1,83 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	newServer := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if body == "" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+
+	house := newServer(`[{"pwr":350}]`)
+	defer house.Close()
+	garage := newServer(`[{"pwr":-120}]`)
+	defer garage.Close()
+	broken := newServer("")
+	defer broken.Close()
+
+	newClient := func(name, url string) *Client {
+		c, err := NewClient(Config{BaseURL: url, Name: name})
+		assert.NoError(t, err)
+		return c
+	}
+
+	t.Run("add and get", func(t *testing.T) {
+		var reg Registry
+		c := newClient("house", house.URL)
+		assert.NoError(t, reg.Add("", c))
+		assert.NoError(t, reg.Add("other", c))
+		assert.ErrorIs(t, reg.Add("house", c), ErrDuplicateClient)
+		assert.ErrorIs(t, reg.Add("", &Client{}), ErrMissingName)
+
+		have, ok := reg.Get("house")
+		assert.True(t, ok)
+		assert.Same(t, c, have)
+		assert.Equal(t, []string{"house", "other"}, reg.Names())
+
+		reg.Remove("other")
+		_, ok = reg.Get("other")
+		assert.False(t, ok)
+	})
+	t.Run("snapshot all", func(t *testing.T) {
+		reg, err := NewRegistry(
+			newClient("house", house.URL),
+			newClient("garage", garage.URL),
+		)
+		assert.NoError(t, err)
+
+		have, err := reg.SnapshotAll(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, have, 2)
+		assert.Equal(t, int64(350), have["house"].Power)
+		assert.Equal(t, int64(-120), have["garage"].Power)
+	})
+	t.Run("partial failure", func(t *testing.T) {
+		reg, err := NewRegistry(
+			newClient("house", house.URL),
+			newClient("broken", broken.URL),
+		)
+		assert.NoError(t, err)
+
+		have, err := reg.SnapshotAll(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), `"broken"`)
+		assert.Len(t, have, 1)
+		assert.Contains(t, have, "house")
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type requestID struct{}
+
+// WithRequestID returns a copy of ctx which carries id. The id is added as
+// AttrRequestID attribute to the spans of requests made with the returned
+// context, and is included in the messages of the default Logger.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestID{}, id)
+}
+
+// RequestID returns the request id carried by ctx, as set with WithRequestID.
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestID{}).(string)
+	return id, ok && id != ""
+}
+
+// NewRequestID returns a random 16 character hexadecimal request id. It is the
+// default generator used by WithRequestIDGenerator.
+func NewRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
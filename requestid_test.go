@@ -0,0 +1,91 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// attrTracer is a trace.Tracer which records the attributes set on its spans
+// after they are started.
+type attrTracer struct {
+	trace.Tracer
+	mut   sync.Mutex
+	attrs []attribute.KeyValue
+}
+
+func (t *attrTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	ctx, span := t.Tracer.Start(ctx, name, opts...)
+	return ctx, &attrSpan{Span: span, tracer: t}
+}
+
+type attrSpan struct {
+	trace.Span
+	tracer *attrTracer
+}
+
+func (s *attrSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.tracer.mut.Lock()
+	s.tracer.attrs = append(s.tracer.attrs, kv...)
+	s.tracer.mut.Unlock()
+}
+
+func TestRequestID(t *testing.T) {
+	_, ok := RequestID(context.Background())
+	assert.False(t, ok)
+
+	id, ok := RequestID(WithRequestID(context.Background(), "abc"))
+	assert.True(t, ok)
+	assert.Equal(t, "abc", id)
+
+	assert.Len(t, NewRequestID(), 16)
+	assert.NotEqual(t, NewRequestID(), NewRequestID())
+}
+
+func TestClient_requestID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	t.Run("from context", func(t *testing.T) {
+		var buf strings.Builder
+		tracer := &attrTracer{Tracer: noop.NewTracerProvider().Tracer("")}
+		c, err := NewClient(Config{BaseURL: srv.URL, Name: "test"},
+			WithTracer(tracer),
+			WithDeviceInfo(DeviceInfoResponse{}),
+			WithLogger(NewLogger(log.New(&buf, "", 0))),
+		)
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(WithRequestID(context.Background(), "abc"))
+		assert.NoError(t, err)
+		assert.Equal(t, "[abc] client test requesting "+srv.URL+"/e (shared: false)\n", buf.String())
+		assert.Contains(t, tracer.attrs, attribute.String(AttrRequestID, "abc"))
+	})
+	t.Run("generated", func(t *testing.T) {
+		var buf strings.Builder
+		c, err := NewClient(Config{BaseURL: srv.URL, Name: "test"},
+			WithRequestIDGenerator(func() string { return "gen" }),
+			WithLogger(NewLogger(log.New(&buf, "", 0))),
+		)
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "[gen] client test requesting "+srv.URL+"/e (shared: false)\n", buf.String())
+	})
+}
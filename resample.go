@@ -0,0 +1,99 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrResampleInterval errors.Msg = "cannot resample to interval"
+
+// ByTime returns the TimedValues of the LogResponse keyed by their time.
+// Values which cannot be parsed are omitted.
+func (r LogResponse) ByTime() map[time.Time]TimedValue {
+	values, _ := r.TimedValues()
+	res := make(map[time.Time]TimedValue, len(values))
+	for _, tv := range values {
+		res[tv.Time] = tv
+	}
+	return res
+}
+
+// Resample downsamples the TimedValues of the LogResponse to the coarser
+// Interval i. Each resulting value starts at a boundary of i on the clock of
+// the location of the values' times, e.g. at midnight for PerDay, and combines
+// the active values within it. Values of energy and volume units are summed,
+// values of power units (e.g. Watt) are averaged. A resulting value is
+// inactive when all of its values are inactive.
+// It returns an ErrResampleInterval error when i is finer than, or not a
+// multiple of, the Interval of the LogResponse.
+func (r LogResponse) Resample(i Interval) ([]TimedValue, error) {
+	from, to := r.Interval.Duration(), i.Duration()
+	if from <= 0 || to < from || to%from != 0 {
+		return nil, errors.Wrapf(ErrResampleInterval,
+			"%s is not a multiple of %s", i.String(), r.Interval.String(),
+		)
+	}
+
+	values, err := r.TimedValues()
+	if err != nil {
+		return nil, err
+	}
+
+	kind, _ := r.Unit.kind()
+	res := make([]TimedValue, 0, len(values)/int(to/from)+1)
+
+	var n int64
+	flush := func() {
+		last := &res[len(res)-1]
+		if kind == power && n > 0 {
+			last.Value /= n
+		}
+	}
+
+	for _, tv := range values {
+		start := bucketStart(tv.Time, i)
+		if len(res) == 0 || !res[len(res)-1].Time.Equal(start) {
+			if len(res) != 0 {
+				flush()
+			}
+			res = append(res, TimedValue{Time: start, Inactive: true})
+			n = 0
+		}
+		if tv.Inactive {
+			continue
+		}
+
+		last := &res[len(res)-1]
+		last.Value += tv.Value
+		last.Inactive = false
+		n++
+	}
+	if len(res) != 0 {
+		flush()
+	}
+	return res, nil
+}
+
+// bucketStart returns the start of the Interval i which contains t, on the
+// clock of t's location. Unlike time.Truncate, which uses absolute time,
+// this respects locations with an offset which is not a multiple of i.
+func bucketStart(t time.Time, i Interval) time.Time {
+	if i == PerDay {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	}
+
+	// subtract the part of the clock which is within the interval, this keeps
+	// the offset of t intact when a daylight saving time change repeats an
+	// hour
+	step := int(i.Duration() / time.Minute)
+	offset := time.Duration(t.Minute()%step)*time.Minute +
+		time.Duration(t.Second())*time.Second +
+		time.Duration(t.Nanosecond())
+	return t.Add(-offset)
+}
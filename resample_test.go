@@ -0,0 +1,113 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogResponse_ByTime(t *testing.T) {
+	r := LogResponse{
+		Unit:      Watt,
+		Timestamp: "2024-01-28T12:00:00",
+		Interval:  Per10min,
+		RawValues: []string{"120", "*", ""},
+		loc:       time.UTC,
+	}
+
+	start := time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, map[time.Time]TimedValue{
+		start:                       {Time: start, Value: 120},
+		start.Add(10 * time.Minute): {Time: start.Add(10 * time.Minute), Inactive: true},
+	}, r.ByTime())
+}
+
+func TestLogResponse_Resample(t *testing.T) {
+	hour := func(h int) time.Time { return time.Date(2024, 1, 28, h, 0, 0, 0, time.UTC) }
+
+	t.Run("sum energy", func(t *testing.T) {
+		r := LogResponse{
+			Unit:      KWh,
+			Timestamp: "2024-01-27T22:00:00",
+			Interval:  PerHour,
+			RawValues: []string{"1", "2", "3", "4", ""},
+			loc:       time.UTC,
+		}
+		have, err := r.Resample(PerDay)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimedValue{
+			{Time: time.Date(2024, 1, 27, 0, 0, 0, 0, time.UTC), Value: 3},
+			{Time: time.Date(2024, 1, 28, 0, 0, 0, 0, time.UTC), Value: 7},
+		}, have)
+	})
+	t.Run("average power", func(t *testing.T) {
+		r := LogResponse{
+			Unit:      Watt,
+			Timestamp: "2024-01-28T11:40:00",
+			Interval:  Per10min,
+			RawValues: []string{"100", "200", "300", "*", "500", "*", "*", "*", "*", "*", "*", "*", "*", ""},
+			loc:       time.UTC,
+		}
+		have, err := r.Resample(PerHour)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimedValue{
+			{Time: hour(11), Value: 150},
+			{Time: hour(12), Value: 400},
+			{Time: hour(13), Inactive: true},
+		}, have)
+	})
+	t.Run("same interval", func(t *testing.T) {
+		r := LogResponse{
+			Unit:      Watt,
+			Timestamp: "2024-01-28T12:00:00",
+			Interval:  PerHour,
+			RawValues: []string{"100", "200"},
+			loc:       time.UTC,
+		}
+		have, err := r.Resample(PerHour)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimedValue{
+			{Time: hour(12), Value: 100},
+			{Time: hour(13), Value: 200},
+		}, have)
+	})
+	t.Run("location", func(t *testing.T) {
+		// india has an offset of +5:30, which is not aligned to the hour
+		loc := time.FixedZone("IST", 5*60*60+30*60)
+		r := LogResponse{
+			Unit:      KWh,
+			Timestamp: "2024-01-27T22:00:00",
+			Interval:  PerHour,
+			RawValues: []string{"1", "2", "3", "4", ""},
+			loc:       loc,
+		}
+
+		have, err := r.Resample(PerDay)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimedValue{
+			{Time: time.Date(2024, 1, 27, 0, 0, 0, 0, loc), Value: 3},
+			{Time: time.Date(2024, 1, 28, 0, 0, 0, 0, loc), Value: 7},
+		}, have)
+
+		r.Interval = Per10min
+		r.Timestamp = "2024-01-28T11:40:00"
+		r.Unit = Watt
+		r.RawValues = []string{"100", "200", "300", "500"}
+		have, err = r.Resample(PerHour)
+		assert.NoError(t, err)
+		assert.Equal(t, []TimedValue{
+			{Time: time.Date(2024, 1, 28, 11, 0, 0, 0, loc), Value: 150},
+			{Time: time.Date(2024, 1, 28, 12, 0, 0, 0, loc), Value: 400},
+		}, have)
+	})
+	t.Run("finer interval", func(t *testing.T) {
+		r := LogResponse{Unit: Watt, Interval: PerHour}
+		_, err := r.Resample(Per10min)
+		assert.ErrorIs(t, err, ErrResampleInterval)
+	})
+}
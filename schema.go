@@ -0,0 +1,97 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ResponseSchemas returns a JSON Schema-like description of the responses of
+// the device's api, keyed by the name of their struct. Each schema describes
+// the wire names of the fields as properties, with their type and, when
+// applicable, unit. The result can be marshalled to JSON, e.g. to generate a
+// client in another language.
+func ResponseSchemas() map[string]any {
+	return map[string]any{
+		"BasicStatusResponse":  schemaOf(BasicStatusResponse{}),
+		"DeviceInfoResponse":   schemaOf(DeviceInfoResponse{}),
+		"LogResponse":          schemaOf(LogResponse{}),
+		"MeterReadingResponse": schemaOf(MeterReadingResponse{}, secondaryS0JSON{}),
+		"PhaseReadingResponse": schemaOf(PhaseReadingResponse{}),
+	}
+}
+
+// schemaOf returns the object schema of the json fields of the provided
+// structs.
+func schemaOf(structs ...any) map[string]any {
+	props := make(map[string]any)
+	for _, v := range structs {
+		addSchemaProps(props, reflect.TypeOf(v))
+	}
+	return map[string]any{
+		"type":       "object",
+		"properties": props,
+	}
+}
+
+func addSchemaProps(props map[string]any, typ reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addSchemaProps(props, field.Type)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		prop := typeSchema(field.Type)
+		prop["title"] = field.Name
+		if unit := field.Tag.Get("unit"); unit != "" {
+			prop["unit"] = unit
+		}
+		props[name] = prop
+	}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema returns the schema of a value of typ. Pointers are described as
+// nullable values of the type they point to.
+func typeSchema(typ reflect.Type) map[string]any {
+	if typ.Kind() == reflect.Pointer {
+		s := typeSchema(typ.Elem())
+		s["nullable"] = true
+		return s
+	}
+	if typ == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": typeSchema(typ.Elem())}
+	case reflect.Struct:
+		return schemaOf(reflect.Zero(typ).Interface())
+	default:
+		return map[string]any{}
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseSchemas(t *testing.T) {
+	schemas := ResponseSchemas()
+
+	meter := schemas["MeterReadingResponse"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "integer", "title": "Power", "unit": "W"}, meter["pwr"])
+	assert.Equal(t, map[string]any{"type": "number", "title": "ElectricityImport1", "unit": "kWh"}, meter["p1"])
+	assert.Equal(t, map[string]any{"type": "number", "title": "GasTotal", "unit": "m3"}, meter["gas"])
+	assert.Equal(t, map[string]any{"type": "number", "title": "S0Total", "unit": "kWh", "nullable": true}, meter["cs1"])
+	assert.Len(t, meter, 17)
+
+	log := schemas["LogResponse"].(map[string]any)["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{
+		"type":  "array",
+		"title": "RawValues",
+		"items": map[string]any{"type": "string"},
+	}, log["val"])
+
+	_, err := json.Marshal(schemas)
+	assert.NoError(t, err)
+}
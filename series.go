@@ -0,0 +1,100 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const (
+	ErrSeriesGap        errors.Msg = "gap in series"
+	ErrSeriesDuplicate  errors.Msg = "duplicate time in series"
+	ErrSeriesUnsorted   errors.Msg = "series is not sorted"
+	ErrSeriesMisaligned errors.Msg = "time is not aligned to interval"
+)
+
+// SortTimedValues sorts values by their Time in ascending order. Values with
+// the same Time keep their original order.
+func SortTimedValues(values []TimedValue) {
+	slices.SortStableFunc(values, func(a, b TimedValue) int {
+		return a.Time.Compare(b.Time)
+	})
+}
+
+// SeriesProblem describes a range within a series of TimedValues which is not
+// continuous.
+type SeriesProblem struct {
+	// Err is one of ErrSeriesGap, ErrSeriesDuplicate, ErrSeriesUnsorted or
+	// ErrSeriesMisaligned.
+	Err errors.Msg
+	// Start and End are the times of the values surrounding the problem. For
+	// a gap these are the last value before and first value after the gap,
+	// for a duplicate both equal the duplicated time.
+	Start, End time.Time
+}
+
+func (p SeriesProblem) Error() string {
+	return fmt.Sprintf("%s: %s - %s", p.Err, p.Start.Format(time.RFC3339), p.End.Format(time.RFC3339))
+}
+
+// Unwrap returns Err so errors.Is can be used to check the kind of problem.
+func (p SeriesProblem) Unwrap() error { return p.Err }
+
+// ValidateTimedValues checks if values form a continuous series, sorted by
+// time, with exactly Interval i between each value. It returns a
+// SeriesProblem for each range which does not. Use SortTimedValues to sort
+// values first. Values of PerDay are compared by calendar date in the
+// location of their Time, so days with a daylight saving time change are not
+// reported as a problem.
+func ValidateTimedValues(values []TimedValue, i Interval) []SeriesProblem {
+	dt := i.Duration()
+
+	var res []SeriesProblem
+	for n := 1; n < len(values); n++ {
+		prev, cur := values[n-1].Time, values[n].Time
+
+		var err errors.Msg
+		switch step := cur.Sub(prev); {
+		case step == 0:
+			if last := len(res) - 1; last >= 0 && res[last].Err == ErrSeriesDuplicate && res[last].Start.Equal(cur) {
+				// the time is repeated more than twice
+				continue
+			}
+			err = ErrSeriesDuplicate
+		case step < 0:
+			err = ErrSeriesUnsorted
+		case i == PerDay:
+			days := daysBetween(prev, cur)
+			if !prev.AddDate(0, 0, days).Equal(cur) {
+				err = ErrSeriesMisaligned
+			} else if days == 1 {
+				continue
+			} else {
+				err = ErrSeriesGap
+			}
+		case step == dt:
+			continue
+		case step%dt != 0:
+			err = ErrSeriesMisaligned
+		default:
+			err = ErrSeriesGap
+		}
+		res = append(res, SeriesProblem{Err: err, Start: prev, End: cur})
+	}
+	return res
+}
+
+// daysBetween returns the number of calendar days from the date of a to the
+// date of b, each in the location of their own time.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	d := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC))
+	return int(d / (24 * time.Hour))
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTimedValues(t *testing.T) {
+	tm := time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	values := []TimedValue{
+		{Time: tm.Add(2 * time.Hour), Value: 3},
+		{Time: tm, Value: 1},
+		{Time: tm.Add(time.Hour), Value: 2},
+		{Time: tm, Value: 4},
+	}
+
+	SortTimedValues(values)
+	assert.Equal(t, []TimedValue{
+		{Time: tm, Value: 1},
+		{Time: tm, Value: 4},
+		{Time: tm.Add(time.Hour), Value: 2},
+		{Time: tm.Add(2 * time.Hour), Value: 3},
+	}, values)
+}
+
+func TestValidateTimedValues(t *testing.T) {
+	tm := time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	at := func(minutes ...int) []TimedValue {
+		res := make([]TimedValue, 0, len(minutes))
+		for _, m := range minutes {
+			res = append(res, TimedValue{Time: tm.Add(time.Duration(m) * time.Minute)})
+		}
+		return res
+	}
+
+	tests := map[string]struct {
+		values []TimedValue
+		want   []SeriesProblem
+	}{
+		"continuous": {
+			values: at(0, 10, 20, 30),
+		},
+		"empty": {},
+		"gap": {
+			values: at(0, 10, 40, 50),
+			want: []SeriesProblem{
+				{Err: ErrSeriesGap, Start: tm.Add(10 * time.Minute), End: tm.Add(40 * time.Minute)},
+			},
+		},
+		"duplicates": {
+			values: at(0, 10, 10, 10, 20),
+			want: []SeriesProblem{
+				{Err: ErrSeriesDuplicate, Start: tm.Add(10 * time.Minute), End: tm.Add(10 * time.Minute)},
+			},
+		},
+		"unsorted": {
+			values: at(0, 20, 10),
+			want: []SeriesProblem{
+				{Err: ErrSeriesGap, Start: tm, End: tm.Add(20 * time.Minute)},
+				{Err: ErrSeriesUnsorted, Start: tm.Add(20 * time.Minute), End: tm.Add(10 * time.Minute)},
+			},
+		},
+		"misaligned": {
+			values: at(0, 15, 25),
+			want: []SeriesProblem{
+				{Err: ErrSeriesMisaligned, Start: tm, End: tm.Add(15 * time.Minute)},
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ValidateTimedValues(tc.values, Per10min))
+		})
+	}
+}
+
+func TestValidateTimedValues_perDay(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+
+	at := func(days ...int) []TimedValue {
+		res := make([]TimedValue, 0, len(days))
+		for _, d := range days {
+			// daylight saving time starts at March 31, 2024
+			res = append(res, TimedValue{Time: time.Date(2024, 3, 30+d, 0, 0, 0, 0, loc)})
+		}
+		return res
+	}
+
+	t.Run("dst change", func(t *testing.T) {
+		assert.Nil(t, ValidateTimedValues(at(0, 1, 2), PerDay))
+	})
+	t.Run("gap", func(t *testing.T) {
+		values := at(0, 1, 3)
+		assert.Equal(t, []SeriesProblem{
+			{Err: ErrSeriesGap, Start: values[1].Time, End: values[2].Time},
+		}, ValidateTimedValues(values, PerDay))
+	})
+	t.Run("misaligned", func(t *testing.T) {
+		values := at(0, 1)
+		values[1].Time = values[1].Time.Add(time.Hour)
+		assert.Equal(t, []SeriesProblem{
+			{Err: ErrSeriesMisaligned, Start: values[0].Time, End: values[1].Time},
+		}, ValidateTimedValues(values, PerDay))
+	})
+}
+
+func TestSeriesProblem_Error(t *testing.T) {
+	tm := time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	var err error = SeriesProblem{Err: ErrSeriesGap, Start: tm, End: tm.Add(time.Hour)}
+
+	assert.ErrorIs(t, err, ErrSeriesGap)
+	assert.False(t, errors.Is(err, ErrSeriesDuplicate))
+	assert.Equal(t, "gap in series: 2024-01-28T12:00:00Z - 2024-01-28T13:00:00Z", err.Error())
+}
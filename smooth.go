@@ -0,0 +1,101 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"math"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidSmoothingAlpha errors.Msg = "smoothing alpha must be > 0 and <= 1"
+
+// SmoothedMeterReading is a MeterReadingResponse of which Power contains the
+// exponential moving average of the power of successive readings.
+type SmoothedMeterReading struct {
+	MeterReadingResponse
+	// RawPower is the power in Watt as reported by the device, before it
+	// was smoothed.
+	RawPower int64
+}
+
+// PowerSmoother applies an exponential moving average (EMA) to the power of
+// successive meter readings.
+type PowerSmoother struct {
+	alpha float64
+	avg   float64
+	init  bool
+}
+
+// NewPowerSmoother returns a PowerSmoother with smoothing factor alpha. A
+// smaller alpha results in a smoother, but slower responding, power. An alpha
+// of 1 disables smoothing. It returns an ErrInvalidSmoothingAlpha error when
+// alpha is not within (0, 1].
+func NewPowerSmoother(alpha float64) (*PowerSmoother, error) {
+	if !(alpha > 0 && alpha <= 1) {
+		return nil, errors.Wrapf(ErrInvalidSmoothingAlpha, "alpha %v", alpha)
+	}
+	return &PowerSmoother{alpha: alpha}, nil
+}
+
+// Smooth adds the power of r to the moving average and returns r with its
+// Power replaced by the average. The first reading is used as is.
+func (s *PowerSmoother) Smooth(r MeterReadingResponse) SmoothedMeterReading {
+	raw := float64(r.Power)
+	if s.init {
+		s.avg += s.alpha * (raw - s.avg)
+	} else {
+		s.avg, s.init = raw, true
+	}
+
+	res := SmoothedMeterReading{
+		MeterReadingResponse: r,
+		RawPower:             r.Power,
+	}
+	res.Power = int64(math.Round(s.avg))
+	return res
+}
+
+// Reset clears the moving average, the next reading passed to Smooth is used
+// as is.
+func (s *PowerSmoother) Reset() { s.avg, s.init = 0, false }
+
+// SmoothPower applies an exponential moving average with smoothing factor
+// alpha to the power of the readings received from in, e.g. the reading
+// channel of StreamMeterReading. The returned channel is unbuffered and
+// closed when in is closed or ctx is canceled; the caller must keep receiving
+// from it until then, or cancel ctx. See NewPowerSmoother for valid values of
+// alpha.
+func SmoothPower(ctx context.Context, in <-chan MeterReadingResponse, alpha float64) (<-chan SmoothedMeterReading, error) {
+	s, err := NewPowerSmoother(alpha)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan SmoothedMeterReading)
+	go func() {
+		defer close(out)
+		for {
+			var r MeterReadingResponse
+			select {
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				r = v
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case out <- s.Smooth(r):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
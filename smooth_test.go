@@ -0,0 +1,94 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPowerSmoother(t *testing.T) {
+	for _, alpha := range []float64{0, -0.5, 1.5} {
+		_, err := NewPowerSmoother(alpha)
+		assert.ErrorIs(t, err, ErrInvalidSmoothingAlpha, "alpha %v", alpha)
+	}
+}
+
+func TestPowerSmoother_Smooth(t *testing.T) {
+	reading := func(pwr int64) MeterReadingResponse {
+		return MeterReadingResponse{ElectricityReading: ElectricityReading{Power: pwr}}
+	}
+
+	t.Run("ema", func(t *testing.T) {
+		s, err := NewPowerSmoother(0.5)
+		assert.NoError(t, err)
+
+		var have []int64
+		for _, pwr := range []int64{100, 300, 300, 0} {
+			r := s.Smooth(reading(pwr))
+			assert.Equal(t, pwr, r.RawPower)
+			have = append(have, r.Power)
+		}
+		assert.Equal(t, []int64{100, 200, 250, 125}, have)
+
+		s.Reset()
+		assert.Equal(t, int64(80), s.Smooth(reading(80)).Power)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		s, err := NewPowerSmoother(1)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), s.Smooth(reading(100)).Power)
+		assert.Equal(t, int64(900), s.Smooth(reading(900)).Power)
+	})
+}
+
+func TestSmoothPower(t *testing.T) {
+	in := make(chan MeterReadingResponse, 2)
+	in <- MeterReadingResponse{ElectricityReading: ElectricityReading{Power: 100, Timestamp: 1}}
+	in <- MeterReadingResponse{ElectricityReading: ElectricityReading{Power: 500, Timestamp: 2}}
+	close(in)
+
+	out, err := SmoothPower(context.Background(), in, 0.25)
+	assert.NoError(t, err)
+
+	var have []SmoothedMeterReading
+	for r := range out {
+		have = append(have, r)
+	}
+	assert.Len(t, have, 2)
+	assert.Equal(t, int64(200), have[1].Power)
+	assert.Equal(t, int64(500), have[1].RawPower)
+	assert.Equal(t, int64(2), have[1].Timestamp)
+
+	_, err = SmoothPower(context.Background(), in, 0)
+	assert.ErrorIs(t, err, ErrInvalidSmoothingAlpha)
+
+	t.Run("cancel", func(t *testing.T) {
+		in := make(chan MeterReadingResponse, 1)
+		in <- MeterReadingResponse{ElectricityReading: ElectricityReading{Power: 100}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		out, err := SmoothPower(ctx, in, 0.25)
+		assert.NoError(t, err)
+
+		// the reading is never received, the goroutine must stop anyway
+		assert.Eventually(t, func() bool { return len(in) == 0 }, time.Second, time.Millisecond)
+		cancel()
+
+		select {
+		case _, ok := <-out:
+			if ok {
+				// the send may have won the race with cancel
+				_, ok = <-out
+			}
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel not closed after cancel")
+		}
+	})
+}
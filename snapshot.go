@@ -0,0 +1,89 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+// Snapshot concurrently requests the device info, meter reading and phase
+// reading. When any of the requests fail, the results of the successful
+// requests are still returned together with the errors of the failed ones.
+func (api *apiRequester) Snapshot(ctx context.Context) (DeviceInfoResponse, MeterReadingResponse, PhaseReadingResponse, error) {
+	var (
+		info  DeviceInfoResponse
+		meter MeterReadingResponse
+		phase PhaseReadingResponse
+	)
+
+	res, err := api.GetSnapshot(ctx)
+	if res.Device != nil {
+		info = *res.Device
+	}
+	if res.Meter != nil {
+		meter = *res.Meter
+	}
+	if res.Phase != nil {
+		phase = *res.Phase
+	}
+	return info, meter, phase, err
+}
+
+// SnapshotResponse combines the results of Snapshot in a single response which
+// can be marshalled to json. Results of failed requests are nil.
+type SnapshotResponse struct {
+	// CollectedAt is the time at which all requests of the snapshot
+	// completed.
+	CollectedAt time.Time             `json:"collected_at"`
+	Device      *DeviceInfoResponse   `json:"device,omitempty"`
+	Meter       *MeterReadingResponse `json:"meter,omitempty"`
+	Phase       *PhaseReadingResponse `json:"phase,omitempty"`
+}
+
+// GetSnapshot is similar to Snapshot, except it combines the results in a
+// SnapshotResponse. When any of the requests fail, the SnapshotResponse with
+// the results of the successful requests is returned together with the errors
+// of the failed ones.
+func (api *apiRequester) GetSnapshot(ctx context.Context) (SnapshotResponse, error) {
+	var (
+		res SnapshotResponse
+
+		wg                          sync.WaitGroup
+		infoErr, meterErr, phaseErr error
+	)
+
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		info, err := api.GetDeviceInfo(ctx)
+		if infoErr = err; err == nil {
+			res.Device = &info
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		meter, err := api.GetMeterReading(ctx)
+		if meterErr = err; err == nil {
+			res.Meter = &meter
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		phase, err := api.GetPhaseReading(ctx)
+		if phaseErr = err; err == nil {
+			res.Phase = &phase
+		}
+	}()
+	wg.Wait()
+	res.CollectedAt = time.Now()
+
+	var err error
+	errors.AppendInto(&err, infoErr, meterErr, phaseErr)
+	return res, err
+}
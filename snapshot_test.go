@@ -0,0 +1,77 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_Snapshot(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+			switch path {
+			case "d":
+				return json.Unmarshal([]byte(`{"model":"LS120"}`), out)
+			case "e":
+				return json.Unmarshal([]byte(`[{"pwr":350}]`), out)
+			case "f":
+				return json.Unmarshal([]byte(`{"v1":231.2}`), out)
+			}
+			t.Fatalf("unexpected request to %s", path)
+			return nil
+		})}
+
+		info, meter, phase, err := api.Snapshot(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "LS120", info.Model)
+		assert.Equal(t, int64(350), meter.Power)
+		assert.Equal(t, 231.2, phase.Voltage1)
+	})
+	t.Run("partial", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+			if path == "e" {
+				return json.Unmarshal([]byte(`[{"pwr":350}]`), out)
+			}
+			return ErrUnsupportedByFirmware
+		})}
+
+		_, meter, _, err := api.Snapshot(context.Background())
+		assert.ErrorIs(t, err, ErrUnsupportedByFirmware)
+		assert.Equal(t, int64(350), meter.Power)
+	})
+}
+
+func TestAPIRequester_GetSnapshot(t *testing.T) {
+	api := &apiRequester{requesterFunc(func(_ context.Context, path string, out any) error {
+		switch path {
+		case "d":
+			return json.Unmarshal([]byte(`{"model":"LS120","fw":"1.6.1-EL","mac":"72:b8:ad:14:16:2e"}`), out)
+		case "e":
+			return json.Unmarshal([]byte(`[{"tm":1706443200,"pwr":350}]`), out)
+		}
+		return ErrUnsupportedByFirmware
+	})}
+
+	before := time.Now()
+	have, err := api.GetSnapshot(context.Background())
+	assert.ErrorIs(t, err, ErrUnsupportedByFirmware)
+	assert.Nil(t, have.Phase)
+	assert.Equal(t, int64(350), have.Meter.Power)
+	assert.False(t, have.CollectedAt.Before(before))
+
+	have.CollectedAt = time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC)
+	b, err := json.Marshal(have)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"collected_at":"2024-01-28T12:00:00Z",`+
+		`"device":{"model":"LS120","fw":"1.6.1-EL","mac":"72:b8:ad:14:16:2e"},`+
+		`"meter":{"tm":1706443200,"p1":0,"p2":0,"n1":0,"n2":0,"net":0,"pwr":350,"ts0":0,"cs0":0,"ps0":0,"gts":0,"gas":0,"wts":0,"wtr":0}}`,
+		string(b),
+	)
+}
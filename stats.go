@@ -0,0 +1,62 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Stats contains statistics of the requests made by a Client.
+type Stats struct {
+	// Requests is the total amount of requests.
+	Requests uint64
+	// Shared is the amount of requests which shared the result of another,
+	// identical request instead of sending a request to the device.
+	Shared uint64
+	// Errors is the amount of requests which resulted in an error.
+	Errors uint64
+	// AverageLatency is the average duration of the requests which were
+	// actually sent to the device.
+	AverageLatency time.Duration
+}
+
+type stats struct {
+	requests atomic.Uint64
+	shared   atomic.Uint64
+	errors   atomic.Uint64
+	// sent is the amount of requests sent to the device, latency is their
+	// total duration in nanoseconds
+	sent    atomic.Uint64
+	latency atomic.Int64
+}
+
+func (s *stats) record(shared bool, err error) {
+	s.requests.Add(1)
+	if shared {
+		s.shared.Add(1)
+	}
+	if err != nil {
+		s.errors.Add(1)
+	}
+}
+
+func (s *stats) recordLatency(d time.Duration) {
+	s.sent.Add(1)
+	s.latency.Add(int64(d))
+}
+
+// Stats returns statistics of the requests made by the Client.
+func (c *Client) Stats() Stats {
+	res := Stats{
+		Requests: c.stats.requests.Load(),
+		Shared:   c.stats.shared.Load(),
+		Errors:   c.stats.errors.Load(),
+	}
+	if sent := c.stats.sent.Load(); sent > 0 {
+		res.AverageLatency = time.Duration(c.stats.latency.Load() / int64(sent))
+	}
+	return res
+}
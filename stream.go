@@ -0,0 +1,84 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidStreamInterval errors.Msg = "stream interval must be positive"
+
+// StreamMeterReading requests a meter reading immediately and after each
+// interval, and sends the results to the returned reading channel until ctx is
+// canceled. Errors are sent to the returned error channel without stopping the
+// stream, unless the error is fatal (e.g. ErrInvalidPassword). When interval
+// is not positive, an ErrInvalidStreamInterval error is sent and the stream
+// stops immediately. Both channels are unbuffered and closed when the stream
+// stops; the caller must keep receiving from both of them until then, or
+// cancel ctx, otherwise the stream blocks.
+func (api *apiRequester) StreamMeterReading(ctx context.Context, interval time.Duration) (<-chan MeterReadingResponse, <-chan error) {
+	resCh := make(chan MeterReadingResponse)
+	errCh := make(chan error)
+
+	go func() {
+		defer close(resCh)
+		defer close(errCh)
+
+		if interval <= 0 {
+			select {
+			case errCh <- errors.New(ErrInvalidStreamInterval):
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			res, err := api.GetMeterReading(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				select {
+				case errCh <- err:
+				case <-ctx.Done():
+					return
+				}
+				if isFatal(err) {
+					return
+				}
+			} else {
+				select {
+				case resCh <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return resCh, errCh
+}
+
+// isFatal indicates if err is an error which will not resolve itself when
+// retrying the same request.
+func isFatal(err error) bool {
+	return errors.Is(err, ErrInvalidPassword) ||
+		errors.Is(err, ErrReadPasswordFile) ||
+		errors.Is(err, ErrUnsupportedByFirmware) ||
+		errors.Is(err, ErrClientClosed) ||
+		errors.Is(err, ErrAuthLockout)
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRequester_StreamMeterReading(t *testing.T) {
+	t.Run("readings", func(t *testing.T) {
+		var n int64
+		api := &apiRequester{requesterFunc(func(_ context.Context, _ string, out any) error {
+			n++
+			if n == 2 {
+				return errors.New("temporary error")
+			}
+			*out.(*[]MeterReadingResponse) = []MeterReadingResponse{{
+				ElectricityReading: ElectricityReading{Power: n},
+			}}
+			return nil
+		})}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		resCh, errCh := api.StreamMeterReading(ctx, time.Millisecond)
+
+		assert.Equal(t, int64(1), (<-resCh).Power)
+		assert.Error(t, <-errCh)
+		assert.Equal(t, int64(3), (<-resCh).Power)
+		cancel()
+
+		for range resCh {
+		}
+		_, ok := <-errCh
+		assert.False(t, ok)
+	})
+	t.Run("fatal error", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(context.Context, string, any) error {
+			return errors.New(ErrInvalidPassword)
+		})}
+
+		resCh, errCh := api.StreamMeterReading(context.Background(), time.Millisecond)
+		assert.ErrorIs(t, <-errCh, ErrInvalidPassword)
+
+		_, ok := <-resCh
+		assert.False(t, ok)
+	})
+	t.Run("invalid interval", func(t *testing.T) {
+		api := &apiRequester{requesterFunc(func(context.Context, string, any) error {
+			t.Fatal("unexpected request")
+			return nil
+		})}
+
+		resCh, errCh := api.StreamMeterReading(context.Background(), 0)
+		assert.ErrorIs(t, <-errCh, ErrInvalidStreamInterval)
+
+		_, ok := <-resCh
+		assert.False(t, ok)
+	})
+}
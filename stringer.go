@@ -0,0 +1,77 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"strconv"
+	"strings"
+)
+
+// String returns a compact summary of the DeviceInfoResponse, e.g.
+// "model=LS120 firmware=1.6.1-EL mac=72:b8:ad:14:16:2e".
+func (r DeviceInfoResponse) String() string {
+	return "model=" + r.Model + " firmware=" + r.Firmware + " mac=" + r.MAC
+}
+
+// String returns a compact summary of the MeterReadingResponse, e.g.
+// "power=350W import=2200.579kWh export=966.012kWh gas=456.789m³". S0, gas
+// and water readings are only included when present.
+func (r MeterReadingResponse) String() string {
+	var sb strings.Builder
+	sb.WriteString("power=")
+	sb.WriteString(strconv.FormatInt(r.Power, 10))
+	sb.WriteString("W import=")
+	writeFloat(&sb, r.ElectricityImport1+r.ElectricityImport2)
+	sb.WriteString("kWh export=")
+	writeFloat(&sb, r.ElectricityExport1+r.ElectricityExport2)
+	sb.WriteString("kWh")
+
+	if r.S0Timestamp != 0 {
+		sb.WriteString(" s0=")
+		sb.WriteString(strconv.FormatInt(r.S0, 10))
+		sb.WriteString("W s0total=")
+		writeFloat(&sb, r.S0Total)
+		sb.WriteString("kWh")
+	}
+	if r.GasReading.HasReading() {
+		sb.WriteString(" gas=")
+		writeFloat(&sb, r.GasTotal)
+		sb.WriteString("m³")
+	}
+	if r.WaterReading.HasReading() {
+		sb.WriteString(" water=")
+		writeFloat(&sb, r.WaterTotal)
+		sb.WriteString("m³")
+	}
+	return sb.String()
+}
+
+// String returns a compact summary of the PhaseReadingResponse, e.g.
+// "tariff=2 L1=231.2V/1.52A/350W". Only phases which are in use are included.
+func (r PhaseReadingResponse) String() string {
+	var sb strings.Builder
+	sb.WriteString("tariff=")
+	sb.WriteString(strconv.FormatUint(uint64(r.Tariff), 10))
+
+	for i, p := range [...]PhaseReading{r.Phase1(), r.Phase2(), r.Phase3()} {
+		if !p.InUse() {
+			continue
+		}
+		sb.WriteString(" L")
+		sb.WriteString(strconv.Itoa(i + 1))
+		sb.WriteByte('=')
+		writeFloat(&sb, p.Voltage)
+		sb.WriteString("V/")
+		writeFloat(&sb, p.Current)
+		sb.WriteString("A/")
+		sb.WriteString(strconv.FormatInt(p.Power, 10))
+		sb.WriteByte('W')
+	}
+	return sb.String()
+}
+
+func writeFloat(sb *strings.Builder, f float64) {
+	sb.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceInfoResponse_String(t *testing.T) {
+	have := DeviceInfoResponse{Model: "LS120", Firmware: "1.6.1-EL", MAC: "72:b8:ad:14:16:2e"}
+	assert.Equal(t, "model=LS120 firmware=1.6.1-EL mac=72:b8:ad:14:16:2e", fmt.Sprint(have))
+}
+
+func TestMeterReadingResponse_String(t *testing.T) {
+	tests := map[string]struct {
+		input MeterReadingResponse
+		want  string
+	}{
+		"electricity only": {
+			input: MeterReadingResponse{ElectricityReading: ElectricityReading{
+				Power:              350,
+				ElectricityImport1: 1000.5,
+				ElectricityImport2: 234,
+			}},
+			want: "power=350W import=1234.5kWh export=0kWh",
+		},
+		"with gas and water": {
+			input: MeterReadingResponse{
+				ElectricityReading: ElectricityReading{Power: -120, ElectricityExport1: 12.25},
+				GasReading:         GasReading{GasTimestamp: 2401011200, GasTotal: 456.7},
+				WaterReading:       WaterReading{WaterTimestamp: 2401011200, WaterTotal: 12.3},
+			},
+			want: "power=-120W import=0kWh export=12.25kWh gas=456.7m³ water=12.3m³",
+		},
+		"with s0": {
+			input: MeterReadingResponse{S0Reading: S0Reading{S0Timestamp: 1704106800, S0Total: 3.5, S0: 80}},
+			want:  "power=0W import=0kWh export=0kWh s0=80W s0total=3.5kWh",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, fmt.Sprintf("%v", tc.input))
+		})
+	}
+}
+
+func TestPhaseReadingResponse_String(t *testing.T) {
+	have := PhaseReadingResponse{
+		Tariff:   2,
+		Current1: 1.52, Power1: 350, Voltage1: 231.2,
+		Current3: 0.4, Power3: 90, Voltage3: 229,
+	}
+	assert.Equal(t, "tariff=2 L1=231.2V/1.52A/350W L3=229V/0.4A/90W", have.String())
+}
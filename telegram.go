@@ -0,0 +1,216 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidTelegram errors.Msg = "invalid P1 telegram"
+
+// TelegramTimeLayout is the layout of the timestamps in a P1 telegram, without
+// the trailing DST indicator. Its format is "YYMMDDhhmmss".
+const TelegramTimeLayout = "060102150405"
+
+// OBIS codes of the common fields of a P1 telegram.
+const (
+	obisTime          = "0-0:1.0.0"
+	obisImportTariff1 = "1-0:1.8.1"
+	obisImportTariff2 = "1-0:1.8.2"
+	obisExportTariff1 = "1-0:2.8.1"
+	obisExportTariff2 = "1-0:2.8.2"
+	obisTariff        = "0-0:96.14.0"
+	obisPowerImport   = "1-0:1.7.0"
+	obisPowerExport   = "1-0:2.7.0"
+	obisGas           = "0-1:24.2.1"
+	obisEquipmentID   = "0-0:96.1.1"
+	obisTextMessage   = "0-0:96.13.0"
+)
+
+var (
+	// the meter's local time is always CET in winter and CEST in summer
+	telegramWinter = time.FixedZone("CET", 1*60*60)
+	telegramSummer = time.FixedZone("CEST", 2*60*60)
+)
+
+// ParsedTelegram contains the common fields of a P1 telegram. Energy is in
+// kWh, power in kW and gas in m3.
+type ParsedTelegram struct {
+	Header        string    `json:"header"`
+	Time          time.Time `json:"time"`
+	Tariff        int       `json:"tariff"`
+	ImportTariff1 float64   `json:"import_tariff1"`
+	ImportTariff2 float64   `json:"import_tariff2"`
+	ExportTariff1 float64   `json:"export_tariff1"`
+	ExportTariff2 float64   `json:"export_tariff2"`
+	PowerImport   float64   `json:"power_import"`
+	PowerExport   float64   `json:"power_export"`
+	Gas           float64   `json:"gas"`
+	GasTime       time.Time `json:"gas_time"`
+	// EquipmentID is the decoded equipment identifier of the meter.
+	EquipmentID string `json:"equipment_id,omitempty"`
+	// TextMessage is the decoded text message sent by the grid operator,
+	// e.g. a notice of an outage. It is empty when there is no message.
+	TextMessage string `json:"text_message,omitempty"`
+	// Extra contains the raw values of all other OBIS codes in the telegram.
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// Parse parses the P1 telegram's Data. Values of OBIS codes which are not a
+// field of ParsedTelegram are added to ParsedTelegram.Extra.
+func (r P1TelegramResponse) Parse() (ParsedTelegram, error) {
+	var res ParsedTelegram
+
+	scanner := bufio.NewScanner(bytes.NewReader(r.Data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if line[0] == '/' {
+			res.Header = line[1:]
+			continue
+		}
+		if line[0] == '!' {
+			break
+		}
+
+		obis, rest, ok := strings.Cut(line, "(")
+		if !ok || !strings.HasSuffix(rest, ")") {
+			return res, errors.Wrapf(ErrInvalidTelegram, "line %q", line)
+		}
+		values := strings.Split(rest[:len(rest)-1], ")(")
+		if err := res.set(obis, values); err != nil {
+			return res, errors.Wrapf(err, "line %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return res, errors.WithStack(err)
+	}
+	return res, nil
+}
+
+func (t *ParsedTelegram) set(obis string, values []string) (err error) {
+	switch obis {
+	case obisTime:
+		t.Time, err = parseTelegramTime(values[0])
+	case obisImportTariff1:
+		t.ImportTariff1, err = parseTelegramValue(values[0])
+	case obisImportTariff2:
+		t.ImportTariff2, err = parseTelegramValue(values[0])
+	case obisExportTariff1:
+		t.ExportTariff1, err = parseTelegramValue(values[0])
+	case obisExportTariff2:
+		t.ExportTariff2, err = parseTelegramValue(values[0])
+	case obisPowerImport:
+		t.PowerImport, err = parseTelegramValue(values[0])
+	case obisPowerExport:
+		t.PowerExport, err = parseTelegramValue(values[0])
+	case obisTariff:
+		t.Tariff, err = strconv.Atoi(values[0])
+	case obisEquipmentID:
+		t.EquipmentID = decodeTelegramText(values[0])
+	case obisTextMessage:
+		t.TextMessage = decodeTelegramText(values[0])
+	case obisGas:
+		if len(values) != 2 {
+			return errors.New(ErrInvalidTelegram)
+		}
+		if t.GasTime, err = parseTelegramTime(values[0]); err == nil {
+			t.Gas, err = parseTelegramValue(values[1])
+		}
+	default:
+		if t.Extra == nil {
+			t.Extra = make(map[string]string, 8)
+		}
+		t.Extra[obis] = strings.Join(values, ")(")
+	}
+	if err != nil {
+		return errors.Wrap(err, ErrInvalidTelegram)
+	}
+	return nil
+}
+
+// parseTelegramValue parses a value like "001000.123*kWh", the unit is
+// ignored.
+func parseTelegramValue(s string) (float64, error) {
+	s, _, _ = strings.Cut(s, "*")
+	return strconv.ParseFloat(s, 64)
+}
+
+// decodeTelegramText decodes a hex encoded text value like "4530303033". A
+// value which is not hex encoded is returned as is, as some meters send plain
+// text.
+func decodeTelegramText(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
+
+// parseTelegramTime parses a timestamp like "240128120000W", where the last
+// character indicates winter (W) or summer (S) time.
+func parseTelegramTime(s string) (time.Time, error) {
+	if len(s) != len(TelegramTimeLayout)+1 {
+		return time.Time{}, errors.New(ErrInvalidTelegram)
+	}
+
+	loc := telegramWinter
+	if s[len(s)-1] == 'S' {
+		loc = telegramSummer
+	}
+	return time.ParseInLocation(TelegramTimeLayout, s[:len(s)-1], loc)
+}
+
+// MarshalJSON marshals the ParsedTelegram to json, with its timestamps
+// formatted as RFC3339. A zero GasTime is omitted.
+func (t ParsedTelegram) MarshalJSON() ([]byte, error) {
+	type alias ParsedTelegram
+	v := struct {
+		alias
+		Time    string `json:"time"`
+		GasTime string `json:"gas_time,omitempty"`
+	}{alias: alias(t)}
+
+	if !t.Time.IsZero() {
+		v.Time = t.Time.Format(time.RFC3339)
+	}
+	if !t.GasTime.IsZero() {
+		v.GasTime = t.GasTime.Format(time.RFC3339)
+	}
+	return json.Marshal(v)
+}
+
+// MeterReadingFromTelegram maps the ParsedTelegram to a MeterReadingResponse,
+// so readings are uniform regardless of the device's firmware. S0 and water
+// readings are not part of a P1 telegram and are left empty.
+func MeterReadingFromTelegram(t ParsedTelegram) MeterReadingResponse {
+	var res MeterReadingResponse
+	if !t.Time.IsZero() {
+		res.Timestamp = t.Time.Unix()
+	}
+	res.ElectricityImport1 = t.ImportTariff1
+	res.ElectricityImport2 = t.ImportTariff2
+	res.ElectricityExport1 = t.ExportTariff1
+	res.ElectricityExport2 = t.ExportTariff2
+	res.NetElectricity = t.ImportTariff1 + t.ImportTariff2 - t.ExportTariff1 - t.ExportTariff2
+	res.Power = int64(math.Round((t.PowerImport - t.PowerExport) * 1000))
+
+	if !t.GasTime.IsZero() {
+		res.GasTimestamp, _ = strconv.ParseUint(t.GasTime.Format(TimestampLayout), 10, 64)
+		res.GasTotal = t.Gas
+	}
+	return res
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTelegram = "/XMX5LGBBFG1012463155\r\n\r\n" +
+	"1-3:0.2.8(42)\r\n" +
+	"0-0:1.0.0(240128120000W)\r\n" +
+	"0-0:96.1.1(4530303033303030303030303030303030)\r\n" +
+	"1-0:1.8.1(001000.123*kWh)\r\n" +
+	"1-0:1.8.2(001200.456*kWh)\r\n" +
+	"1-0:2.8.1(000400.001*kWh)\r\n" +
+	"1-0:2.8.2(000566.011*kWh)\r\n" +
+	"0-0:96.14.0(0002)\r\n" +
+	"1-0:1.7.0(00.350*kW)\r\n" +
+	"1-0:2.7.0(00.000*kW)\r\n" +
+	"0-0:96.13.0(53746F72696E67)\r\n" +
+	"0-1:24.2.1(240128120000W)(00456.789*m3)\r\n" +
+	"!1A2B\r\n"
+
+func TestP1TelegramResponse_Parse(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		have, err := P1TelegramResponse{Data: []byte(testTelegram)}.Parse()
+		assert.NoError(t, err)
+
+		cet := time.FixedZone("CET", 60*60)
+		assert.Equal(t, ParsedTelegram{
+			Header:        "XMX5LGBBFG1012463155",
+			Time:          time.Date(2024, 1, 28, 12, 0, 0, 0, cet),
+			Tariff:        2,
+			ImportTariff1: 1000.123,
+			ImportTariff2: 1200.456,
+			ExportTariff1: 400.001,
+			ExportTariff2: 566.011,
+			PowerImport:   0.35,
+			Gas:           456.789,
+			GasTime:       time.Date(2024, 1, 28, 12, 0, 0, 0, cet),
+			EquipmentID:   "E0003000000000000",
+			TextMessage:   "Storing",
+			Extra: map[string]string{
+				"1-3:0.2.8": "42",
+			},
+		}, have)
+	})
+	t.Run("plain text message", func(t *testing.T) {
+		have, err := P1TelegramResponse{Data: []byte("0-0:96.13.0(outage at 12:00)\r\n")}.Parse()
+		assert.NoError(t, err)
+		assert.Equal(t, "outage at 12:00", have.TextMessage)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := P1TelegramResponse{Data: []byte("1-0:1.8.1(abc*kWh)\r\n")}.Parse()
+		assert.ErrorIs(t, err, ErrInvalidTelegram)
+	})
+}
+
+func TestParsedTelegram_MarshalJSON(t *testing.T) {
+	tg, err := P1TelegramResponse{Data: []byte(testTelegram)}.Parse()
+	assert.NoError(t, err)
+
+	have, err := json.Marshal(tg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"header":"XMX5LGBBFG1012463155",
+		"time":"2024-01-28T12:00:00+01:00",
+		"tariff":2,
+		"import_tariff1":1000.123,
+		"import_tariff2":1200.456,
+		"export_tariff1":400.001,
+		"export_tariff2":566.011,
+		"power_import":0.35,
+		"power_export":0,
+		"gas":456.789,
+		"gas_time":"2024-01-28T12:00:00+01:00",
+		"equipment_id":"E0003000000000000",
+		"text_message":"Storing",
+		"extra":{"1-3:0.2.8":"42"}
+	}`, string(have))
+}
+
+func TestMeterReadingFromTelegram(t *testing.T) {
+	tg, err := P1TelegramResponse{Data: []byte(testTelegram)}.Parse()
+	assert.NoError(t, err)
+
+	have := MeterReadingFromTelegram(tg)
+	assert.Equal(t, int64(1706439600), have.Timestamp)
+	assert.Equal(t, 1000.123, have.ElectricityImport1)
+	assert.Equal(t, 1200.456, have.ElectricityImport2)
+	assert.Equal(t, 400.001, have.ElectricityExport1)
+	assert.Equal(t, 566.011, have.ElectricityExport2)
+	assert.InDelta(t, 1234.567, have.NetElectricity, 0.0001)
+	assert.Equal(t, int64(350), have.Power)
+	assert.Equal(t, uint64(2401281200), have.GasTimestamp)
+	assert.Equal(t, 456.789, have.GasTotal)
+	assert.False(t, have.WaterReading.HasReading())
+
+	t.Run("empty", func(t *testing.T) {
+		assert.Equal(t, MeterReadingResponse{}, MeterReadingFromTelegram(ParsedTelegram{}))
+	})
+}
+
+func TestWithTelegramFallback(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/e":
+			w.WriteHeader(http.StatusNotFound)
+		case "/V":
+			if r.URL.Query().Get("p") == "1" {
+				_, _ = w.Write([]byte(testTelegram))
+			}
+		default:
+			t.Fatalf("unexpected request %s", r.URL)
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("enabled", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL}, WithTelegramFallback())
+		assert.NoError(t, err)
+
+		have, err := c.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(350), have.Power)
+		assert.Equal(t, 456.789, have.GasTotal)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		c, err := NewClient(Config{BaseURL: srv.URL})
+		assert.NoError(t, err)
+
+		_, err = c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, ErrUnsupportedByFirmware)
+	})
+}
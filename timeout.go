@@ -0,0 +1,40 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type callTimeout struct{}
+
+// WithCallTimeout returns a copy of ctx which overrides Config.Timeout for
+// all requests made with it. Calls which consist of multiple requests, like
+// GetP1Telegram, use the timeout as budget for all of their requests.
+func WithCallTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, callTimeout{}, timeout)
+}
+
+type timeoutBudget struct{}
+
+// budget contains the deadline shared by all requests of a single call. The
+// deadline is determined when the first request is made.
+type budget struct {
+	once sync.Once
+	end  time.Time
+}
+
+func (b *budget) deadline(timeout time.Duration) time.Time {
+	b.once.Do(func() { b.end = time.Now().Add(timeout) })
+	return b.end
+}
+
+// withTimeoutBudget returns a copy of ctx which makes all requests made with
+// it share a single timeout.
+func withTimeoutBudget(ctx context.Context) context.Context {
+	return context.WithValue(ctx, timeoutBudget{}, new(budget))
+}
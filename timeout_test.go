@@ -0,0 +1,44 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCallTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(40 * time.Millisecond)
+		if r.URL.Path == "/V" {
+			// never reach the end of the telegram within the first pages
+			_, _ = w.Write([]byte("1-0:1.8.1(000123.456*kWh)\r\n"))
+			return
+		}
+		_, _ = w.Write([]byte(`[{"pwr":350}]`))
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{BaseURL: srv.URL, Timeout: 10 * time.Millisecond})
+	assert.NoError(t, err)
+
+	t.Run("override", func(t *testing.T) {
+		_, err := c.GetMeterReading(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		_, err = c.GetMeterReading(WithCallTimeout(context.Background(), time.Second))
+		assert.NoError(t, err)
+	})
+	t.Run("budget", func(t *testing.T) {
+		// each page fits within the timeout, all pages combined do not
+		_, err := c.GetP1Telegram(WithCallTimeout(context.Background(), 100*time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
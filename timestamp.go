@@ -16,7 +16,9 @@ import (
 const TimestampLayout = "0601021504"
 
 // ParseTimestamp parses a timestamp from a uint64 in layout TimestampLayout to
-// a time.Time.
+// a time.Time in UTC. As the device reports timestamps in its local time, use
+// ParseTimestampInLocation or Client.ParseTimestamp to interpret it in the
+// device's location instead.
 func ParseTimestamp(ts uint64) (time.Time, error) {
 	t, err := parseTimestamp(ts)
 	if err != nil {
@@ -25,17 +27,75 @@ func ParseTimestamp(ts uint64) (time.Time, error) {
 	return t, nil
 }
 
+// ParseTimestampInLocation is similar to ParseTimestamp, except it interprets
+// the timestamp as local time in loc.
+func ParseTimestampInLocation(ts uint64, loc *time.Location) (time.Time, error) {
+	t, err := parseTimestampInLocation(ts, loc)
+	if err != nil {
+		return t, errors.WithStack(err)
+	}
+	return t, nil
+}
+
+// ParseTimestamp parses a timestamp from a uint64 in layout TimestampLayout
+// to a time.Time in the location of the device, see WithLocation.
+func (c *Client) ParseTimestamp(ts uint64) (time.Time, error) {
+	return ParseTimestampInLocation(ts, c.Location())
+}
+
 func parseTimestamp(ts uint64) (time.Time, error) {
 	return time.Parse(TimestampLayout, strconv.FormatUint(ts, 10))
 }
 
-func (r GasReading) Time() time.Time {
-	t, _ := parseTimestamp(r.GasTimestamp)
+func parseTimestampInLocation(ts uint64, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(TimestampLayout, strconv.FormatUint(ts, 10), loc)
+}
+
+// locationOrLocal returns loc, or time.Local when loc is nil.
+func locationOrLocal(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.Local
+	}
+	return loc
+}
+
+// HasReading indicates if the GasReading contains an actual meter reading.
+// The device reports a zero timestamp until it has received the first reading.
+func (r GasReading) HasReading() bool { return r.GasTimestamp != 0 }
+
+// Time returns GasTimestamp as time.Time. It returns a zero time.Time when
+// there is no reading. The timestamp is the device's local time, it is
+// interpreted in the location of the Client which requested the reading (see
+// WithLocation), or time.Local when unknown.
+func (r GasReading) Time() time.Time { return r.TimeIn(r.loc) }
+
+// TimeIn is similar to Time, except it interprets GasTimestamp as local time
+// in loc, or time.Local when loc is nil.
+func (r GasReading) TimeIn(loc *time.Location) time.Time {
+	if !r.HasReading() {
+		return time.Time{}
+	}
+	t, _ := parseTimestampInLocation(r.GasTimestamp, locationOrLocal(loc))
 	return t
 }
 
-func (r WaterReading) Time() time.Time {
-	t, _ := parseTimestamp(r.WaterTimestamp)
+// HasReading indicates if the WaterReading contains an actual meter reading.
+// The device reports a zero timestamp until it has received the first reading.
+func (r WaterReading) HasReading() bool { return r.WaterTimestamp != 0 }
+
+// Time returns WaterTimestamp as time.Time. It returns a zero time.Time when
+// there is no reading. The timestamp is the device's local time, it is
+// interpreted in the location of the Client which requested the reading (see
+// WithLocation), or time.Local when unknown.
+func (r WaterReading) Time() time.Time { return r.TimeIn(r.loc) }
+
+// TimeIn is similar to Time, except it interprets WaterTimestamp as local
+// time in loc, or time.Local when loc is nil.
+func (r WaterReading) TimeIn(loc *time.Location) time.Time {
+	if !r.HasReading() {
+		return time.Time{}
+	}
+	t, _ := parseTimestampInLocation(r.WaterTimestamp, locationOrLocal(loc))
 	return t
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	_ "time/tzdata"
 )
 
 func TestParseTimestamp(t *testing.T) {
@@ -23,16 +24,50 @@ func TestParseTimestamp(t *testing.T) {
 	})
 }
 
+func TestParseTimestampInLocation(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+
+	// daylight saving time starts at 2024-03-31 02:00 CET
+	tests := map[uint64]time.Time{
+		2403310130: time.Date(2024, 3, 31, 0, 30, 0, 0, time.UTC),
+		2403310330: time.Date(2024, 3, 31, 1, 30, 0, 0, time.UTC),
+		2401281200: time.Date(2024, 1, 28, 11, 0, 0, 0, time.UTC),
+		2407281200: time.Date(2024, 7, 28, 10, 0, 0, 0, time.UTC),
+	}
+	for ts, want := range tests {
+		have, err := ParseTimestampInLocation(ts, loc)
+		assert.NoError(t, err)
+		assert.Equal(t, want, have.UTC(), "%d", ts)
+	}
+}
+
 func TestReadingResponse_GasTime(t *testing.T) {
 	var r MeterReadingResponse
 	r.GasTimestamp = 2401281200
-	assert.Equal(t, time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC), r.GasReading.Time())
+	assert.True(t, r.GasReading.HasReading())
+	assert.Equal(t, time.Date(2024, 1, 28, 12, 0, 0, 0, time.Local), r.GasReading.Time())
+	assert.Equal(t, time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC), r.GasReading.TimeIn(time.UTC))
+
+	t.Run("no reading", func(t *testing.T) {
+		var r MeterReadingResponse
+		assert.False(t, r.GasReading.HasReading())
+		assert.True(t, r.GasReading.Time().IsZero())
+	})
 }
 
 func TestReadingResponse_WaterTime(t *testing.T) {
 	var r MeterReadingResponse
 	r.WaterTimestamp = 2401281200
-	assert.Equal(t, time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC), r.WaterReading.Time())
+	assert.True(t, r.WaterReading.HasReading())
+	assert.Equal(t, time.Date(2024, 1, 28, 12, 0, 0, 0, time.Local), r.WaterReading.Time())
+	assert.Equal(t, time.Date(2024, 1, 28, 12, 0, 0, 0, time.UTC), r.WaterReading.TimeIn(time.UTC))
+
+	t.Run("no reading", func(t *testing.T) {
+		var r MeterReadingResponse
+		assert.False(t, r.WaterReading.HasReading())
+		assert.True(t, r.WaterReading.Time().IsZero())
+	})
 }
 
 func TestToTimestamp(t *testing.T) {
@@ -0,0 +1,90 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"github.com/go-pogo/errors"
+)
+
+//goland:noinspection GoUnusedConst
+const (
+	Watt       Unit = "Watt"
+	KiloWatt   Unit = "kW"
+	WattHour   Unit = "Wh"
+	KWh        Unit = "kWh"
+	Liter      Unit = "L"
+	CubicMeter Unit = "m3"
+
+	ErrIncompatibleUnits errors.Msg = "incompatible units"
+	ErrUnitMismatch      errors.Msg = "unit mismatch"
+)
+
+type Unit string
+
+func (u Unit) String() string { return string(u) }
+
+// UnitMismatchError contains the units of two log pages which are combined,
+// but do not have the same Unit. This happens when a firmware upgrade changed
+// the unit the device reports.
+type UnitMismatchError struct {
+	Want Unit
+	Have Unit
+}
+
+func (e *UnitMismatchError) Error() string {
+	return "expected unit " + e.Want.String() + ", got " + e.Have.String()
+}
+
+// sameUnit returns an ErrUnitMismatch error when have differs from want.
+func sameUnit(want, have Unit) error {
+	if want == have {
+		return nil
+	}
+	return errors.Wrap(&UnitMismatchError{Want: want, Have: have}, ErrUnitMismatch)
+}
+
+type unitKind uint8
+
+const (
+	power unitKind = iota + 1
+	energy
+	volume
+)
+
+// kind returns the kind of quantity the Unit measures and the factor to
+// convert a value in Unit to its base unit (Watt, WattHour or Liter).
+func (u Unit) kind() (unitKind, float64) {
+	switch u {
+	case Watt:
+		return power, 1
+	case KiloWatt:
+		return power, 1000
+	case WattHour:
+		return energy, 1
+	case KWh:
+		return energy, 1000
+	case Liter:
+		return volume, 1
+	case CubicMeter:
+		return volume, 1000
+	default:
+		return 0, 0
+	}
+}
+
+// Convert converts value v from Unit from to Unit to. It returns an
+// ErrIncompatibleUnits error when both units do not measure the same kind of
+// quantity, e.g. Watt and Liter.
+func Convert(v float64, from, to Unit) (float64, error) {
+	fk, ff := from.kind()
+	tk, tf := to.kind()
+	if fk == 0 || fk != tk {
+		return 0, errors.Wrapf(ErrIncompatibleUnits, "cannot convert %s to %s", from, to)
+	}
+	if ff == tf {
+		return v, nil
+	}
+	return v * ff / tf, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youless
+
+import (
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		from, to Unit
+		in, want float64
+	}{
+		{Watt, KiloWatt, 1500, 1.5},
+		{KiloWatt, Watt, 1.5, 1500},
+		{Watt, Watt, 350, 350},
+		{KWh, WattHour, 1.234, 1234},
+		{WattHour, KWh, 500, 0.5},
+		{CubicMeter, Liter, 2.5, 2500},
+		{Liter, CubicMeter, 250, 0.25},
+	}
+	for _, tc := range tests {
+		t.Run(tc.from.String()+" to "+tc.to.String(), func(t *testing.T) {
+			have, err := Convert(tc.in, tc.from, tc.to)
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.want, have, 1e-9)
+		})
+	}
+
+	t.Run("incompatible", func(t *testing.T) {
+		tests := [][2]Unit{
+			{Watt, Liter},
+			{Watt, KWh},
+			{CubicMeter, WattHour},
+			{"x", "x"},
+		}
+		for _, tc := range tests {
+			_, err := Convert(1, tc[0], tc[1])
+			assert.True(t, errors.Is(err, ErrIncompatibleUnits), "%s to %s", tc[0], tc[1])
+		}
+	})
+}
@@ -4,6 +4,14 @@
 
 package youless
 
+import (
+	"strings"
+
+	"github.com/go-pogo/errors"
+)
+
+const ErrInvalidUtility errors.Msg = "invalid utility"
+
 type Utility string
 
 const (
@@ -13,6 +21,15 @@ const (
 	Water       Utility = "water"
 )
 
+// ParseUtility parses s to a valid Utility. It returns an ErrInvalidUtility
+// error when s is not a known Utility.
+func ParseUtility(s string) (Utility, error) {
+	if u := Utility(strings.ToLower(strings.TrimSpace(s))); u.valid() {
+		return u, nil
+	}
+	return "", errors.Wrapf(ErrInvalidUtility, "parse %q", s)
+}
+
 // Endpoint page of the utility's data on the YouLess' api.
 func (s Utility) Endpoint() string {
 	switch s {
@@ -29,6 +46,24 @@ func (s Utility) Endpoint() string {
 	}
 }
 
+// EndpointE is similar to Endpoint, except it returns an ErrInvalidUtility
+// error instead of panicking when Utility is not valid.
+func (s Utility) EndpointE() (string, error) {
+	if !s.valid() {
+		return "", errors.Wrapf(ErrInvalidUtility, "%q", string(s))
+	}
+	return s.Endpoint(), nil
+}
+
+func (s Utility) valid() bool {
+	switch s {
+	case Electricity, S0, Gas, Water:
+		return true
+	default:
+		return false
+	}
+}
+
 // String returns the string representation of Utility.
 func (s Utility) String() string {
 	switch s {
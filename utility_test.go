@@ -44,3 +44,33 @@ func TestUtility_String(t *testing.T) {
 		})
 	})
 }
+
+func TestParseUtility(t *testing.T) {
+	tests := map[string]Utility{
+		"electricity": Electricity,
+		"Gas":         Gas,
+		" water ":     Water,
+		"s0":          S0,
+	}
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			have, err := ParseUtility(input)
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+
+	t.Run("invalid utility", func(t *testing.T) {
+		_, err := ParseUtility("foo")
+		assert.ErrorIs(t, err, ErrInvalidUtility)
+	})
+}
+
+func TestUtility_EndpointE(t *testing.T) {
+	have, err := Gas.EndpointE()
+	assert.NoError(t, err)
+	assert.Equal(t, "W", have)
+
+	_, err = Utility("x").EndpointE()
+	assert.ErrorIs(t, err, ErrInvalidUtility)
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024, Roel Schut. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package youlesstest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-pogo/errors"
+	"github.com/roeldev/youless-client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMockAPI(t *testing.T) {
+	ctx := context.Background()
+	api, mock := NewMockAPI()
+
+	info, err := api.GetDeviceInfo(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "LS120", info.Model)
+
+	meter, err := api.GetMeterReading(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(350), meter.Power)
+
+	phase, err := api.GetPhaseReading(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 231.2, phase.Voltage1)
+
+	telegram, err := api.GetP1Telegram(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, MustFixture(P1TelegramFixture), telegram.Data)
+
+	assert.Equal(t, []string{"d", "e", "f", "V?p=1"}, mock.Calls())
+}
+
+func TestMockRequester_Request(t *testing.T) {
+	t.Run("error", func(t *testing.T) {
+		var m MockRequester
+		wantErr := errors.New("some error")
+		m.HandleError("e", wantErr)
+
+		var out []byte
+		assert.Same(t, wantErr, m.Request(context.Background(), "e", &out))
+	})
+	t.Run("not registered", func(t *testing.T) {
+		var m MockRequester
+		var out []byte
+		assert.ErrorIs(t, m.Request(context.Background(), "e", &out), ErrNoResponse)
+		assert.Equal(t, []string{"e"}, m.Calls())
+	})
+}
+
+func TestMeterReadingFixtures(t *testing.T) {
+	t.Run("single S0", func(t *testing.T) {
+		api, _ := NewMockAPI()
+		have, err := api.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+
+		_, ok := have.SecondaryS0()
+		assert.False(t, ok)
+	})
+	t.Run("dual S0", func(t *testing.T) {
+		api, mock := NewMockAPI()
+		mock.HandleFixture("e", MeterReadingDualS0Fixture)
+
+		have, err := api.GetMeterReading(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, 12.345, have.S0Total)
+
+		s0, ok := have.SecondaryS0()
+		assert.True(t, ok)
+		assert.Equal(t, youless.S0Reading{
+			S0Timestamp: 1706443200,
+			S0Total:     6.789,
+			S0:          120,
+		}, s0)
+	})
+}
+
+func TestMeterReadingCommaFixture(t *testing.T) {
+	api, mock := NewMockAPI()
+	want, err := api.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+
+	mock.HandleFixture("e", MeterReadingCommaFixture)
+	have, err := api.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, want, have)
+}
+
+func TestFixtures_decode(t *testing.T) {
+	api, _ := NewMockAPI()
+	ctx := context.Background()
+
+	t.Run("meter reading", func(t *testing.T) {
+		for _, name := range []string{
+			MeterReadingFixture,
+			MeterReadingDualS0Fixture,
+			MeterReadingCommaFixture,
+		} {
+			t.Run(name, func(t *testing.T) {
+				have, err := youless.DecodeMeterReading(bytes.NewReader(MustFixture(name)))
+				assert.NoError(t, err)
+
+				// round-trip the decoded reading back through json
+				data, err := json.Marshal([]youless.MeterReadingResponse{have})
+				assert.NoError(t, err)
+				again, err := youless.DecodeMeterReading(bytes.NewReader(data))
+				assert.NoError(t, err)
+				assert.Equal(t, have, again)
+			})
+		}
+
+		want, err := api.GetMeterReading(ctx)
+		assert.NoError(t, err)
+		have, err := youless.DecodeMeterReading(bytes.NewReader(MustFixture(MeterReadingFixture)))
+		assert.NoError(t, err)
+		assert.Equal(t, want.ElectricityReading, have.ElectricityReading)
+		assert.Equal(t, want.S0Reading, have.S0Reading)
+	})
+	t.Run("phase reading", func(t *testing.T) {
+		want, err := api.GetPhaseReading(ctx)
+		assert.NoError(t, err)
+		have, err := youless.DecodePhaseReading(bytes.NewReader(MustFixture(PhaseReadingFixture)))
+		assert.NoError(t, err)
+		assert.Equal(t, want, have)
+
+		data, err := json.Marshal(have)
+		assert.NoError(t, err)
+		again, err := youless.DecodePhaseReading(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, have, again)
+	})
+	t.Run("log", func(t *testing.T) {
+		want, err := api.GetLog(ctx, youless.Electricity, youless.Per10min, 1)
+		assert.NoError(t, err)
+		have, err := youless.DecodeLog(bytes.NewReader(MustFixture(LogElectricityFixture)))
+		assert.NoError(t, err)
+		assert.Equal(t, want, have)
+		assert.Equal(t, youless.Watt, have.Unit)
+	})
+}
+
+func TestMeterReadingLegacyFixture(t *testing.T) {
+	api, mock := NewMockAPI()
+	want, err := api.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+
+	mock.HandleFixture("e", MeterReadingLegacyFixture)
+	have, err := api.GetMeterReading(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, want.ElectricityReading, have.ElectricityReading)
+	assert.Equal(t, want.S0Reading, have.S0Reading)
+	assert.False(t, have.GasReading.HasReading())
+
+	decoded, err := youless.DecodeMeterReading(bytes.NewReader(MustFixture(MeterReadingLegacyFixture)))
+	assert.NoError(t, err)
+	assert.Equal(t, have, decoded)
+}
+
+func TestP1TelegramFixtures(t *testing.T) {
+	want, err := youless.P1TelegramResponse{Data: MustFixture(P1TelegramFixture)}.Parse()
+	assert.NoError(t, err)
+
+	for _, name := range []string{P1TelegramFixture, P1TelegramLFFixture} {
+		t.Run(name, func(t *testing.T) {
+			data := MustFixture(name)
+			half := len(data) / 2
+
+			// the telegram spans multiple pages, the last page ends with
+			// the end marker
+			var mock MockRequester
+			mock.Handle("V?p=1", data[:half]).
+				Handle("V?p=2", data[half:])
+
+			telegram, err := youless.NewAPIRequester(&mock).GetP1Telegram(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, data, telegram.Data)
+			assert.Equal(t, []string{"V?p=1", "V?p=2"}, mock.Calls())
+
+			have, err := telegram.Parse()
+			assert.NoError(t, err)
+			assert.Equal(t, want, have)
+		})
+	}
+}